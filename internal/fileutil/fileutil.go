@@ -0,0 +1,102 @@
+// Package fileutil provides utilities to safely share files between
+// goroutines and processes.
+package fileutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mavolin/adam/pkg/errors"
+)
+
+// ErrLockTimeout is the error returned by Lock, if the lock file could not
+// be acquired in time.
+var ErrLockTimeout = errors.New("fileutil: timed out waiting for the lock file")
+
+// lockRetryInterval is the interval in which the lock file is checked, if it
+// already exists.
+const lockRetryInterval = 10 * time.Millisecond
+
+// Lock acquires the lock file of the file with the passed path.
+// The lock file has the same path as the file, suffixed with '.lock'.
+// Lock returns a function that releases the lock.
+//
+// Lock files older than staleAfter are considered stale and are removed.
+// If the lock can't be acquired within timeout, Lock returns ErrLockTimeout.
+func Lock(path string, staleAfter, timeout time.Duration) (unlock func(), err error) {
+	lockPath := path + ".lock"
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+
+		if !os.IsExist(err) {
+			return nil, errors.WithStack(err)
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleAfter {
+			if err = os.Remove(lockPath); err == nil || os.IsNotExist(err) {
+				continue
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, ErrLockTimeout
+		}
+
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// ReadFile reads the file with the passed path.
+// If the file does not exist, ReadFile returns nil, nil.
+func ReadFile(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return data, errors.WithStack(err)
+}
+
+// WriteFile atomically replaces the file with the passed path with a file
+// containing the passed data.
+//
+// To prevent corrupting the file, the data is first written and synced to a
+// temporary file in the same directory, which then replaces the file.
+func WriteFile(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+
+	if err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+
+		return errors.WithStack(err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return errors.WithStack(err)
+	}
+
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package fileutil
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "file.json")
+
+		unlock, err := Lock(path, time.Second, time.Second)
+		require.NoError(t, err)
+		assert.FileExists(t, path+".lock")
+
+		unlock()
+		assert.NoFileExists(t, path+".lock")
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "file.json")
+
+		require.NoError(t, ioutil.WriteFile(path+".lock", nil, 0o600))
+		time.Sleep(time.Millisecond)
+
+		unlock, err := Lock(path, time.Nanosecond, time.Second)
+		require.NoError(t, err)
+		unlock()
+
+		assert.NoFileExists(t, path+".lock")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "file.json")
+
+		require.NoError(t, ioutil.WriteFile(path+".lock", nil, 0o600))
+
+		_, err := Lock(path, time.Minute, 50*time.Millisecond)
+		assert.Equal(t, ErrLockTimeout, err)
+	})
+}
+
+func TestReadFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not exists", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := ReadFile(filepath.Join(t.TempDir(), "file.json"))
+		require.NoError(t, err)
+		assert.Nil(t, data)
+	})
+
+	t.Run("exists", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "file.json")
+		require.NoError(t, ioutil.WriteFile(path, []byte("abc"), 0o600))
+
+		data, err := ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("abc"), data)
+	})
+}
+
+func TestWriteFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.json")
+
+	require.NoError(t, WriteFile(path, []byte("abc")))
+	require.NoError(t, WriteFile(path, []byte("def")))
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("def"), data)
+
+	// the temporary files must have been renamed
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
@@ -1,6 +1,8 @@
 package plugin
 
 import (
+	"time"
+
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/mavolin/disstate/v4/pkg/state"
 
@@ -29,6 +31,7 @@ type Command struct {
 	BotPermissions discord.Permissions
 	Restrictions   plugin.RestrictionFunc
 	Throttler      plugin.Throttler
+	Timeout        time.Duration
 
 	Meta       plugin.CommandMeta
 	InvokeFunc func(*state.State, *plugin.Context) (interface{}, error)
@@ -56,6 +59,7 @@ func (c Command) IsRestricted(s *state.State, ctx *plugin.Context) error {
 }
 
 func (c Command) GetThrottler() plugin.Throttler { return c.Throttler }
+func (c Command) GetTimeout() time.Duration      { return c.Timeout }
 
 func (c Command) Invoke(s *state.State, ctx *plugin.Context) (interface{}, error) {
 	return c.InvokeFunc(s, ctx)
@@ -69,18 +73,40 @@ type Module struct {
 	Name             string
 	ShortDescription string
 	LongDescription  string
+	ChannelTypes     plugin.ChannelTypes
+	BotPermissions   discord.Permissions
+	Restrictions     plugin.RestrictionFunc
+	Throttler        plugin.Throttler
 
 	Commands []plugin.Command
 	Modules  []plugin.Module
 }
 
-var _ plugin.Module = Module{}
+var (
+	_ plugin.Module                = Module{}
+	_ plugin.ModuleThrottler       = Module{}
+	_ plugin.ModuleChannelTyper    = Module{}
+	_ plugin.ModuleBotPermissioner = Module{}
+	_ plugin.ModuleRestricter      = Module{}
+)
 
 func (m Module) GetName() string                            { return m.Name }
 func (m Module) GetShortDescription(*i18n.Localizer) string { return m.ShortDescription }
 func (m Module) GetLongDescription(*i18n.Localizer) string  { return m.LongDescription }
-func (m Module) GetCommands() []plugin.Command              { return m.Commands }
-func (m Module) GetModules() []plugin.Module                { return m.Modules }
+func (m Module) GetChannelTypes() plugin.ChannelTypes       { return m.ChannelTypes }
+func (m Module) GetBotPermissions() discord.Permissions     { return m.BotPermissions }
+
+func (m Module) IsRestricted(s *state.State, ctx *plugin.Context) error {
+	if m.Restrictions == nil {
+		return nil
+	}
+
+	return m.Restrictions(s, ctx)
+}
+
+func (m Module) GetThrottler() plugin.Throttler { return m.Throttler }
+func (m Module) GetCommands() []plugin.Command  { return m.Commands }
+func (m Module) GetModules() []plugin.Module    { return m.Modules }
 
 // =============================================================================
 // Throttler
@@ -132,16 +132,55 @@ func (cmd *Command) ChannelTypes() plugin.ChannelTypes {
 		return t
 	}
 
+	// use the ChannelTypes of the closest parent that defines some
+	for i := len(cmd.sourceParents) - 1; i >= 0; i-- {
+		ct, ok := cmd.sourceParents[i].(plugin.ModuleChannelTyper)
+		if !ok {
+			continue
+		}
+
+		if t := ct.GetChannelTypes(); t > 0 {
+			return t
+		}
+	}
+
 	return plugin.AllChannels
 }
 
-func (cmd *Command) BotPermissions() discord.Permissions { return cmd.source.GetBotPermissions() }
+func (cmd *Command) BotPermissions() discord.Permissions {
+	perms := cmd.source.GetBotPermissions()
+
+	for _, parent := range cmd.sourceParents {
+		if bp, ok := parent.(plugin.ModuleBotPermissioner); ok {
+			perms |= bp.GetBotPermissions()
+		}
+	}
+
+	return perms
+}
 
 func (cmd *Command) IsRestricted(s *state.State, ctx *plugin.Context) error {
-	return cmd.source.IsRestricted(s, ctx)
+	for _, parent := range cmd.sourceParents {
+		r, ok := parent.(plugin.ModuleRestricter)
+		if !ok {
+			continue
+		}
+
+		if err := r.IsRestricted(s, ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := cmd.source.IsRestricted(s, ctx); err != nil {
+		return err
+	}
+
+	return checkProvidedRestrictions(s, ctx, cmd.id)
 }
 
-func (cmd *Command) Throttler() plugin.Throttler { return cmd.source.GetThrottler() }
+func (cmd *Command) Throttler() plugin.Throttler {
+	return newThrottler(cmd.sourceParents, cmd.source)
+}
 
 func (cmd *Command) Invoke(s *state.State, ctx *plugin.Context) (interface{}, error) {
 	return cmd.source.Invoke(s, ctx)
@@ -3,11 +3,14 @@ package resolved
 import (
 	"testing"
 
+	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/mavolin/disstate/v4/pkg/state"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	mockplugin "github.com/mavolin/adam/internal/mock/plugin"
 	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/i18n"
 	"github.com/mavolin/adam/pkg/plugin"
 )
 
@@ -65,3 +68,292 @@ func TestResolvedCommand_IsRestricted(t *testing.T) {
 	actual := rcmd.IsRestricted(nil, nil)
 	assert.Equal(t, expect, actual)
 }
+
+func TestCommand_IsRestricted_parents(t *testing.T) {
+	t.Parallel()
+
+	outerErr := errors.New("outer")
+	innerErr := errors.New("inner")
+	cmdErr := errors.New("command")
+
+	testCases := []struct {
+		name    string
+		parents []plugin.Module
+		cmd     mockplugin.Command
+		expect  error
+	}{
+		{
+			name:    "none",
+			parents: []plugin.Module{mockplugin.Module{}, mockplugin.Module{}},
+			cmd:     mockplugin.Command{},
+			expect:  nil,
+		},
+		{
+			name: "most distant first",
+			parents: []plugin.Module{
+				mockplugin.Module{Restrictions: mockplugin.RestrictionFunc(outerErr)},
+				mockplugin.Module{Restrictions: mockplugin.RestrictionFunc(innerErr)},
+			},
+			cmd:    mockplugin.Command{Restrictions: mockplugin.RestrictionFunc(cmdErr)},
+			expect: outerErr,
+		},
+		{
+			name: "parent before command",
+			parents: []plugin.Module{
+				mockplugin.Module{},
+				mockplugin.Module{Restrictions: mockplugin.RestrictionFunc(innerErr)},
+			},
+			cmd:    mockplugin.Command{Restrictions: mockplugin.RestrictionFunc(cmdErr)},
+			expect: innerErr,
+		},
+		{
+			name:    "command",
+			parents: []plugin.Module{mockplugin.Module{}},
+			cmd:     mockplugin.Command{Restrictions: mockplugin.RestrictionFunc(cmdErr)},
+			expect:  cmdErr,
+		},
+		{
+			name:    "no restricter",
+			parents: []plugin.Module{plainModule{}},
+			cmd:     mockplugin.Command{},
+			expect:  nil,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			rcmd := &Command{source: c.cmd, sourceParents: c.parents}
+
+			actual := rcmd.IsRestricted(nil, new(plugin.Context))
+			assert.Equal(t, c.expect, actual)
+		})
+	}
+}
+
+func TestCommand_ChannelTypes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		parents []plugin.Module
+		cmd     mockplugin.Command
+		expect  plugin.ChannelTypes
+	}{
+		{
+			name:    "default",
+			parents: []plugin.Module{mockplugin.Module{}},
+			cmd:     mockplugin.Command{},
+			expect:  plugin.AllChannels,
+		},
+		{
+			name:    "command",
+			parents: []plugin.Module{mockplugin.Module{ChannelTypes: plugin.GuildChannels}},
+			cmd:     mockplugin.Command{ChannelTypes: plugin.DirectMessages},
+			expect:  plugin.DirectMessages,
+		},
+		{
+			name: "closest parent",
+			parents: []plugin.Module{
+				mockplugin.Module{ChannelTypes: plugin.GuildChannels},
+				mockplugin.Module{ChannelTypes: plugin.GuildTextChannels},
+				mockplugin.Module{},
+			},
+			cmd:    mockplugin.Command{},
+			expect: plugin.GuildTextChannels,
+		},
+		{
+			name: "no channel typer",
+			parents: []plugin.Module{
+				mockplugin.Module{ChannelTypes: plugin.GuildChannels},
+				plainModule{},
+			},
+			cmd:    mockplugin.Command{},
+			expect: plugin.GuildChannels,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			rcmd := &Command{source: c.cmd, sourceParents: c.parents}
+			assert.Equal(t, c.expect, rcmd.ChannelTypes())
+		})
+	}
+}
+
+func TestCommand_BotPermissions(t *testing.T) {
+	t.Parallel()
+
+	rcmd := &Command{
+		source: mockplugin.Command{BotPermissions: discord.PermissionSendMessages},
+		sourceParents: []plugin.Module{
+			mockplugin.Module{BotPermissions: discord.PermissionEmbedLinks},
+			mockplugin.Module{BotPermissions: discord.PermissionAddReactions | discord.PermissionSendMessages},
+			plainModule{},
+		},
+	}
+
+	expect := discord.PermissionSendMessages | discord.PermissionEmbedLinks | discord.PermissionAddReactions
+	assert.Equal(t, expect, rcmd.BotPermissions())
+}
+
+// plainModule is a plugin.Module that implements none of the optional
+// module interfaces.
+type plainModule struct{}
+
+func (plainModule) GetName() string                            { return "plain" }
+func (plainModule) GetShortDescription(*i18n.Localizer) string { return "" }
+func (plainModule) GetLongDescription(*i18n.Localizer) string  { return "" }
+func (plainModule) GetCommands() []plugin.Command              { return nil }
+func (plainModule) GetModules() []plugin.Module                { return nil }
+
+type mockRestrictionProvider map[plugin.ID]plugin.RestrictionFunc
+
+func (p mockRestrictionProvider) Restrictions(_ discord.GuildID, id plugin.ID) (plugin.RestrictionFunc, error) {
+	return p[id], nil
+}
+
+func TestCommand_IsRestricted_provider(t *testing.T) {
+	t.Parallel()
+
+	moduleErr := errors.New("module")
+	commandErr := errors.New("command")
+
+	testCases := []struct {
+		name     string
+		provider plugin.RestrictionProvider
+		expect   error
+	}{
+		{
+			name:     "nil provider",
+			provider: nil,
+			expect:   nil,
+		},
+		{
+			name:     "unrestricted",
+			provider: mockRestrictionProvider{},
+			expect:   nil,
+		},
+		{
+			name: "command",
+			provider: mockRestrictionProvider{
+				".mod.cmd": mockplugin.RestrictionFunc(commandErr),
+			},
+			expect: commandErr,
+		},
+		{
+			name: "module before command",
+			provider: mockRestrictionProvider{
+				".mod":     mockplugin.RestrictionFunc(moduleErr),
+				".mod.cmd": mockplugin.RestrictionFunc(commandErr),
+			},
+			expect: moduleErr,
+		},
+		{
+			name: "other plugin",
+			provider: mockRestrictionProvider{
+				".other": mockplugin.RestrictionFunc(moduleErr),
+			},
+			expect: nil,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			rcmd := &Command{
+				id:     ".mod.cmd",
+				source: mockplugin.Command{},
+			}
+
+			ctx := &plugin.Context{RestrictionProvider: c.provider}
+
+			actual := rcmd.IsRestricted(nil, ctx)
+			assert.Equal(t, c.expect, actual)
+		})
+	}
+}
+
+func TestCommand_Throttler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("none", func(t *testing.T) {
+		t.Parallel()
+
+		rcmd := &Command{
+			source:        mockplugin.Command{},
+			sourceParents: []plugin.Module{mockplugin.Module{}},
+		}
+
+		assert.Nil(t, rcmd.Throttler())
+	})
+
+	t.Run("single", func(t *testing.T) {
+		t.Parallel()
+
+		throttler := mockplugin.NewThrottler(nil)
+
+		rcmd := &Command{
+			source:        mockplugin.Command{},
+			sourceParents: []plugin.Module{mockplugin.Module{Throttler: throttler}},
+		}
+
+		assert.Equal(t, throttler, rcmd.Throttler())
+	})
+
+	t.Run("no module throttler", func(t *testing.T) {
+		t.Parallel()
+
+		throttler := mockplugin.NewThrottler(nil)
+
+		rcmd := &Command{
+			source:        mockplugin.Command{Throttler: throttler},
+			sourceParents: []plugin.Module{plainModule{}},
+		}
+
+		assert.Equal(t, throttler, rcmd.Throttler())
+	})
+
+	t.Run("pass", func(t *testing.T) {
+		t.Parallel()
+
+		moduleThrottler := mockplugin.NewThrottler(nil)
+		cmdThrottler := mockplugin.NewThrottler(nil)
+
+		rcmd := &Command{
+			source:        mockplugin.Command{Throttler: cmdThrottler},
+			sourceParents: []plugin.Module{mockplugin.Module{Throttler: moduleThrottler}},
+		}
+
+		cancelFunc, err := rcmd.Throttler().Check(nil, nil)
+		require.NoError(t, err)
+
+		cancelFunc()
+
+		assert.True(t, moduleThrottler.Canceled)
+		assert.True(t, cmdThrottler.Canceled)
+	})
+
+	t.Run("throttled", func(t *testing.T) {
+		t.Parallel()
+
+		moduleThrottler := mockplugin.NewThrottler(nil)
+		cmdThrottler := mockplugin.NewThrottler(plugin.NewThrottlingError("abc"))
+
+		rcmd := &Command{
+			source:        mockplugin.Command{Throttler: cmdThrottler},
+			sourceParents: []plugin.Module{mockplugin.Module{Throttler: moduleThrottler}},
+		}
+
+		_, err := rcmd.Throttler().Check(nil, nil)
+		assert.Equal(t, plugin.NewThrottlingError("abc"), err)
+		assert.True(t, moduleThrottler.Canceled, "the invoke of the module throttler was not undone")
+	})
+}
@@ -51,3 +51,11 @@ func (r *PluginResolver) AddBuiltInModule(smod plugin.Module) {
 	r.builtinProvider.modules = insertModule(r.builtinProvider.modules,
 		newModule(nil, r.builtinProvider, plugin.BuiltInSource, smod), -1)
 }
+
+func (r *PluginResolver) BuiltInCommands() []plugin.ResolvedCommand {
+	return r.builtinProvider.commands
+}
+
+func (r *PluginResolver) BuiltInModules() []plugin.ResolvedModule {
+	return r.builtinProvider.modules
+}
@@ -0,0 +1,35 @@
+package resolved
+
+import (
+	"github.com/mavolin/disstate/v4/pkg/state"
+
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+// checkProvidedRestrictions checks the restrictions returned by the
+// ctx.RestrictionProvider for the plugin with the passed id and all its
+// parents, starting with root.
+// This way, restrictions configured for a module also apply to all of its
+// children.
+func checkProvidedRestrictions(s *state.State, ctx *plugin.Context, id plugin.ID) error {
+	if ctx.RestrictionProvider == nil {
+		return nil
+	}
+
+	for _, id := range id.All() {
+		f, err := ctx.RestrictionProvider.Restrictions(ctx.GuildID, id)
+		if err != nil {
+			return err
+		}
+
+		if f == nil {
+			continue
+		}
+
+		if err = f(s, ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
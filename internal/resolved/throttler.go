@@ -0,0 +1,76 @@
+package resolved
+
+import (
+	"github.com/mavolin/disstate/v4/pkg/state"
+
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+// throttlers is a plugin.Throttler that combines the throttlers of a command
+// and its parent modules.
+// An invoke is only counted, if none of the throttlers throttles it.
+type throttlers []plugin.Throttler
+
+var _ plugin.ReleasingThrottler = throttlers{}
+
+// newThrottler combines the throttlers of the passed source parents and the
+// passed command.
+// If none of them is throttled, newThrottler returns nil.
+func newThrottler(sourceParents []plugin.Module, scmd plugin.Command) plugin.Throttler {
+	var ts throttlers
+
+	for _, parent := range sourceParents {
+		mt, ok := parent.(plugin.ModuleThrottler)
+		if !ok {
+			continue
+		}
+
+		if t := mt.GetThrottler(); t != nil {
+			ts = append(ts, t)
+		}
+	}
+
+	if t := scmd.GetThrottler(); t != nil {
+		ts = append(ts, t)
+	}
+
+	switch len(ts) {
+	case 0:
+		return nil
+	case 1:
+		return ts[0]
+	default:
+		return ts
+	}
+}
+
+func (ts throttlers) Check(s *state.State, ctx *plugin.Context) (func(), error) {
+	cancelFuncs := make([]func(), 0, len(ts))
+
+	cancel := func() {
+		for _, f := range cancelFuncs {
+			f()
+		}
+	}
+
+	for _, t := range ts {
+		f, err := t.Check(s, ctx)
+		if err != nil {
+			// undo the invokes counted by the preceding throttlers
+			cancel()
+			return nil, err
+		}
+
+		cancelFuncs = append(cancelFuncs, f)
+	}
+
+	return cancel, nil
+}
+
+func (ts throttlers) Release(ctx *plugin.Context) {
+	for _, t := range ts {
+		if rt, ok := t.(plugin.ReleasingThrottler); ok {
+			rt.Release(ctx)
+		}
+	}
+}
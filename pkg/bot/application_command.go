@@ -0,0 +1,555 @@
+package bot
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/i18n"
+	"github.com/mavolin/adam/pkg/impl/arg"
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+const (
+	// maxCommands is the maximum number of application commands of an
+	// application, either globally or in a single guild.
+	maxCommands = 100
+	// maxDescriptionLength is the maximum length of the description of an
+	// application command or option, in characters.
+	maxDescriptionLength = 100
+	// maxOptions is the maximum number of options of an application command,
+	// sub-command group, or sub-command.
+	maxOptions = 25
+	// maxChoices is the maximum number of choices of an option.
+	maxChoices = 25
+)
+
+// GenerateApplicationCommands generates the definitions of the application
+// commands representing the passed commands and modules.
+//
+// Top-level commands become application commands, and top-level modules
+// become application commands with sub-commands and sub-command groups.
+// As Discord only allows a single level of sub-command groups, modules
+// nested deeper than that are omitted.
+// Hidden plugins are omitted as well.
+//
+// The arguments and flags of commands are represented as options.
+// Required arguments come first, followed by optional arguments and flags.
+// The names of arguments are generated using a fallback localizer, whereas
+// all descriptions are generated using the passed *i18n.Localizer.
+//
+// If an option would have more than 25 choices, it is generated without
+// choices, leaving validation to the plugin.ArgType.
+// Similarly, the bounds of arg.Integer and arg.Decimal are only validated by
+// the types themselves.
+// Other limits of Discord, such as the maximum number of options, are not
+// enforced.
+// Use ValidateApplicationCommands to check them.
+//
+// String options without choices, whose type is a plugin.CompletingArgType,
+// have autocomplete enabled.
+// Bot.RouteInteraction answers the resulting autocomplete interactions.
+func GenerateApplicationCommands(
+	l *i18n.Localizer, cmds []plugin.ResolvedCommand, mods []plugin.ResolvedModule,
+) []discord.Command {
+	appCmds := make([]discord.Command, 0, len(cmds)+len(mods))
+
+	for _, cmd := range cmds {
+		if cmd.IsHidden() {
+			continue
+		}
+
+		appCmds = append(appCmds, discord.Command{
+			Name:        applicationCommandName(cmd.Name()),
+			Description: applicationCommandDescription(cmd.ShortDescription(l), cmd.Name()),
+			Options:     argOptions(l, cmd.Args()),
+		})
+	}
+
+	for _, mod := range mods {
+		if mod.IsHidden() {
+			continue
+		}
+
+		opts := moduleOptions(l, mod, true)
+		if len(opts) == 0 {
+			continue
+		}
+
+		appCmds = append(appCmds, discord.Command{
+			Name:        applicationCommandName(mod.Name()),
+			Description: applicationCommandDescription(mod.ShortDescription(l), mod.Name()),
+			Options:     opts,
+		})
+	}
+
+	return appCmds
+}
+
+// moduleOptions returns the sub-commands and, if groups is true, the
+// sub-command groups of the passed module.
+func moduleOptions(l *i18n.Localizer, mod plugin.ResolvedModule, groups bool) []discord.CommandOption {
+	opts := make([]discord.CommandOption, 0, len(mod.Commands())+len(mod.Modules()))
+
+	for _, cmd := range mod.Commands() {
+		if cmd.IsHidden() {
+			continue
+		}
+
+		opts = append(opts, discord.CommandOption{
+			Type:        discord.SubcommandOption,
+			Name:        applicationCommandName(cmd.Name()),
+			Description: applicationCommandDescription(cmd.ShortDescription(l), cmd.Name()),
+			Options:     argOptions(l, cmd.Args()),
+		})
+	}
+
+	if !groups {
+		return opts
+	}
+
+	for _, smod := range mod.Modules() {
+		if smod.IsHidden() {
+			continue
+		}
+
+		subOpts := moduleOptions(l, smod, false)
+		if len(subOpts) == 0 {
+			continue
+		}
+
+		opts = append(opts, discord.CommandOption{
+			Type:        discord.SubcommandGroupOption,
+			Name:        applicationCommandName(smod.Name()),
+			Description: applicationCommandDescription(smod.ShortDescription(l), smod.Name()),
+			Options:     subOpts,
+		})
+	}
+
+	return opts
+}
+
+// argOptions returns the options representing the arguments and flags of the
+// passed plugin.ArgConfig.
+func argOptions(l *i18n.Localizer, cfg plugin.ArgConfig) []discord.CommandOption {
+	if cfg == nil {
+		return nil
+	}
+
+	fallback := i18n.NewFallbackLocalizer()
+
+	rargs := cfg.GetRequiredArgs()
+	oargs := cfg.GetOptionalArgs()
+	flags := cfg.GetFlags()
+
+	opts := make([]discord.CommandOption, 0, len(rargs)+len(oargs)+len(flags))
+
+	for _, a := range rargs {
+		opt := typeOption(l, a.GetType())
+		opt.Name = applicationCommandName(a.GetName(fallback))
+		opt.Description = applicationCommandDescription(a.GetDescription(l), a.GetType().GetName(l))
+		opt.Required = true
+
+		opts = append(opts, opt)
+	}
+
+	for _, a := range oargs {
+		opt := typeOption(l, a.GetType())
+		opt.Name = applicationCommandName(a.GetName(fallback))
+		opt.Description = applicationCommandDescription(a.GetDescription(l), a.GetType().GetName(l))
+
+		opts = append(opts, opt)
+	}
+
+	for _, f := range flags {
+		opt := typeOption(l, f.GetType())
+		opt.Name = applicationCommandName(f.GetName())
+		opt.Description = applicationCommandDescription(f.GetDescription(l), f.GetType().GetName(l))
+
+		opts = append(opts, opt)
+	}
+
+	return opts
+}
+
+// typeOption returns a discord.CommandOption with the type, choices, channel
+// types, and autocomplete set, as derived from the passed
+// plugin.ArgType.
+// Types unknown to typeOption are represented as strings.
+//
+// Autocomplete is only enabled for string options without choices, as
+// Discord doesn't allow autocomplete for options with choices, and completed
+// values are always strings.
+func typeOption(l *i18n.Localizer, typ plugin.ArgType) discord.CommandOption {
+	opt := baseTypeOption(l, typ)
+
+	if _, ok := typ.(plugin.CompletingArgType); ok && opt.Type == discord.StringOption && len(opt.Choices) == 0 {
+		opt.Autocomplete = true
+	}
+
+	return opt
+}
+
+// baseTypeOption returns a discord.CommandOption with the type, choices, and
+// channel types set, as derived from the passed plugin.ArgType.
+//
+//nolint:funlen,gocyclo
+func baseTypeOption(l *i18n.Localizer, typ plugin.ArgType) discord.CommandOption {
+	switch typ {
+	case arg.Switch:
+		return discord.CommandOption{Type: discord.BooleanOption}
+	case arg.User, arg.Member:
+		return discord.CommandOption{Type: discord.UserOption}
+	case arg.Role:
+		return discord.CommandOption{Type: discord.RoleOption}
+	case arg.TextChannel:
+		return discord.CommandOption{
+			Type:         discord.ChannelOption,
+			ChannelTypes: []discord.ChannelType{discord.GuildText, discord.GuildNews},
+		}
+	case arg.Category:
+		return discord.CommandOption{
+			Type:         discord.ChannelOption,
+			ChannelTypes: []discord.ChannelType{discord.GuildCategory},
+		}
+	case arg.VoiceChannel:
+		return discord.CommandOption{
+			Type:         discord.ChannelOption,
+			ChannelTypes: []discord.ChannelType{discord.GuildVoice},
+		}
+	}
+
+	switch typ := typ.(type) {
+	case arg.Integer, *arg.Integer:
+		return discord.CommandOption{Type: discord.IntegerOption}
+	case arg.Decimal, *arg.Decimal:
+		return discord.CommandOption{Type: discord.NumberOption}
+	case arg.Choice:
+		if len(typ) > maxChoices {
+			return discord.CommandOption{Type: discord.StringOption}
+		}
+
+		choices := make([]discord.CommandOptionChoice, len(typ))
+		for i, e := range typ {
+			choices[i] = discord.CommandOptionChoice{Name: e.Name, Value: e.Name}
+		}
+
+		return discord.CommandOption{Type: discord.StringOption, Choices: choices}
+	case arg.LocalizedChoice:
+		choices := make([]discord.CommandOptionChoice, 0, len(typ))
+
+		for _, e := range typ {
+			if len(e.Names) == 0 {
+				continue
+			}
+
+			name, err := l.Localize(e.Names[0])
+			if err != nil {
+				continue
+			}
+
+			choices = append(choices, discord.CommandOptionChoice{Name: name, Value: name})
+		}
+
+		if len(choices) > maxChoices {
+			return discord.CommandOption{Type: discord.StringOption}
+		}
+
+		return discord.CommandOption{Type: discord.StringOption, Choices: choices}
+	default:
+		return discord.CommandOption{Type: discord.StringOption}
+	}
+}
+
+// applicationCommandDescription returns desc shortened to
+// maxDescriptionLength, or fallback, if desc is empty.
+func applicationCommandDescription(desc, fallback string) string {
+	if desc == "" {
+		desc = fallback
+	}
+
+	if utf8.RuneCountInString(desc) > maxDescriptionLength {
+		desc = string([]rune(desc)[:maxDescriptionLength-3]) + "..."
+	}
+
+	return desc
+}
+
+// ValidateApplicationCommands checks if the passed application commands
+// satisfy Discord's limits.
+//
+// It returns an error, if there are more than 100 commands, if a command,
+// sub-command group, or sub-command has more than 25 options, or if a name
+// is used more than once on the same level.
+// The latter happens, if an argument and a flag share the same name, or if
+// a command and a module share the same name.
+func ValidateApplicationCommands(cmds []discord.Command) error {
+	if len(cmds) > maxCommands {
+		return errors.NewWithStackf("bot: %d application commands exceed the limit of %d", len(cmds), maxCommands)
+	}
+
+	names := make(map[string]struct{}, len(cmds))
+
+	for _, cmd := range cmds {
+		if _, ok := names[cmd.Name]; ok {
+			return errors.NewWithStackf("bot: duplicate application command %s", cmd.Name)
+		}
+
+		names[cmd.Name] = struct{}{}
+
+		if err := validateApplicationCommandOptions(cmd.Name, cmd.Options); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateApplicationCommandOptions validates the passed options of the
+// application command, sub-command group, or sub-command with the passed
+// invoke.
+func validateApplicationCommandOptions(invoke string, opts []discord.CommandOption) error {
+	if len(opts) > maxOptions {
+		return errors.NewWithStackf("bot: %s: %d options exceed the limit of %d", invoke, len(opts), maxOptions)
+	}
+
+	names := make(map[string]struct{}, len(opts))
+
+	for _, opt := range opts {
+		if _, ok := names[opt.Name]; ok {
+			return errors.NewWithStackf("bot: %s: duplicate option %s", invoke, opt.Name)
+		}
+
+		names[opt.Name] = struct{}{}
+
+		if err := validateApplicationCommandOptions(invoke+" "+opt.Name, opt.Options); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// =============================================================================
+// Sync
+// =====================================================================================
+
+// ApplicationCommandDiff is the difference between the application commands
+// registered with Discord and the generated ones.
+type ApplicationCommandDiff struct {
+	// Create are the application commands that are not yet registered.
+	Create []discord.Command `json:"create,omitempty"`
+	// Update are the application commands that are registered, but whose
+	// definition changed.
+	// The commands contain the new definition.
+	Update []discord.Command `json:"update,omitempty"`
+	// Delete are the application commands that are registered, but don't
+	// have a generated counterpart anymore.
+	Delete []discord.Command `json:"delete,omitempty"`
+}
+
+// IsEmpty checks if the diff contains no changes.
+func (d *ApplicationCommandDiff) IsEmpty() bool {
+	return len(d.Create) == 0 && len(d.Update) == 0 && len(d.Delete) == 0
+}
+
+// String returns a human-readable representation of the diff, one command per
+// line.
+// Created commands are prefixed with a '+', updated ones with a '~', and
+// deleted ones with a '-'.
+func (d *ApplicationCommandDiff) String() string {
+	var b strings.Builder
+
+	for _, cmd := range d.Create {
+		b.WriteString("+ " + cmd.Name + "\n")
+	}
+
+	for _, cmd := range d.Update {
+		b.WriteString("~ " + cmd.Name + "\n")
+	}
+
+	for _, cmd := range d.Delete {
+		b.WriteString("- " + cmd.Name + "\n")
+	}
+
+	return b.String()
+}
+
+// DiffApplicationCommands computes the difference between the registered and
+// the generated application commands.
+// Commands are matched by name, and only their names, descriptions, and
+// options are compared.
+func DiffApplicationCommands(registered, generated []discord.Command) *ApplicationCommandDiff {
+	var diff ApplicationCommandDiff
+
+	registeredByName := make(map[string]discord.Command, len(registered))
+	for _, cmd := range registered {
+		registeredByName[cmd.Name] = cmd
+	}
+
+	for _, cmd := range generated {
+		rcmd, ok := registeredByName[cmd.Name]
+		if !ok {
+			diff.Create = append(diff.Create, cmd)
+			continue
+		}
+
+		delete(registeredByName, cmd.Name)
+
+		cmd.ID = rcmd.ID
+		if !reflect.DeepEqual(normalizeApplicationCommand(cmd), normalizeApplicationCommand(rcmd)) {
+			diff.Update = append(diff.Update, cmd)
+		}
+	}
+
+	for _, cmd := range registeredByName {
+		diff.Delete = append(diff.Delete, cmd)
+	}
+
+	sort.Slice(diff.Delete, func(i, j int) bool {
+		return diff.Delete[i].Name < diff.Delete[j].Name
+	})
+
+	return &diff
+}
+
+// normalizeApplicationCommand returns a discord.Command containing only the
+// name, description, and options of the passed command, in which all empty
+// slices are nil.
+// This is necessary, as registered commands contain fields set by Discord,
+// and decoded commands use nil slices for omitted fields, whereas generated
+// commands may use empty ones.
+func normalizeApplicationCommand(cmd discord.Command) discord.Command {
+	return discord.Command{
+		Name:        cmd.Name,
+		Description: cmd.Description,
+		Options:     normalizeApplicationCommandOptions(cmd.Options),
+	}
+}
+
+func normalizeApplicationCommandOptions(opts []discord.CommandOption) []discord.CommandOption {
+	if len(opts) == 0 {
+		return nil
+	}
+
+	normalized := make([]discord.CommandOption, len(opts))
+	for i, opt := range opts {
+		if len(opt.Choices) == 0 {
+			opt.Choices = nil
+		}
+
+		if len(opt.ChannelTypes) == 0 {
+			opt.ChannelTypes = nil
+		}
+
+		opt.Options = normalizeApplicationCommandOptions(opt.Options)
+		normalized[i] = opt
+	}
+
+	return normalized
+}
+
+// SyncApplicationCommandsOptions are the options used for
+// Bot.SyncApplicationCommands.
+type SyncApplicationCommandsOptions struct {
+	// GuildID is the id of the guild to register the application commands
+	// in.
+	// If GuildID is 0, the commands will be registered globally.
+	//
+	// Default: 0
+	GuildID discord.GuildID
+	// Localizer is the *i18n.Localizer used to generate the descriptions of
+	// the commands and options.
+	//
+	// Default: i18n.NewFallbackLocalizer()
+	Localizer *i18n.Localizer
+	// DryRun, if true, only computes the diff, but does not overwrite the
+	// registered commands.
+	//
+	// Default: false
+	DryRun bool
+}
+
+// ApplicationCommands returns the definitions of the application commands
+// representing the built-in commands and modules of the bot, as generated by
+// GenerateApplicationCommands.
+func (b *Bot) ApplicationCommands(l *i18n.Localizer) []discord.Command {
+	if l == nil {
+		l = i18n.NewFallbackLocalizer()
+	}
+
+	return GenerateApplicationCommands(l, b.pluginResolver.BuiltInCommands(), b.pluginResolver.BuiltInModules())
+}
+
+// SyncApplicationCommands generates the application commands of the bot and
+// compares them to the registered ones.
+// If they differ, and if o.DryRun is false, the registered commands will be
+// bulk-overwritten with the generated ones.
+//
+// Before making any requests, the generated commands are validated using
+// ValidateApplicationCommands.
+//
+// The returned diff is the diff between the registered and the generated
+// commands, computed before overwriting.
+func (b *Bot) SyncApplicationCommands(o SyncApplicationCommandsOptions) (*ApplicationCommandDiff, error) {
+	return syncApplicationCommands(b.State, b.ApplicationCommands(o.Localizer), o)
+}
+
+// applicationCommandClient is the part of *api.Client used to sync
+// application commands.
+type applicationCommandClient interface {
+	CurrentApplication() (*discord.Application, error)
+	Commands(appID discord.AppID) ([]discord.Command, error)
+	GuildCommands(appID discord.AppID, guildID discord.GuildID) ([]discord.Command, error)
+	BulkOverwriteCommands(appID discord.AppID, cmds []discord.Command) ([]discord.Command, error)
+	BulkOverwriteGuildCommands(
+		appID discord.AppID, guildID discord.GuildID, cmds []discord.Command,
+	) ([]discord.Command, error)
+}
+
+var _ applicationCommandClient = new(api.Client)
+
+// syncApplicationCommands syncs the passed generated commands using the
+// passed client, as described by Bot.SyncApplicationCommands.
+func syncApplicationCommands(
+	c applicationCommandClient, generated []discord.Command, o SyncApplicationCommandsOptions,
+) (*ApplicationCommandDiff, error) {
+	if err := ValidateApplicationCommands(generated); err != nil {
+		return nil, err
+	}
+
+	app, err := c.CurrentApplication()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var registered []discord.Command
+
+	if o.GuildID.IsValid() {
+		registered, err = c.GuildCommands(app.ID, o.GuildID)
+	} else {
+		registered, err = c.Commands(app.ID)
+	}
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	diff := DiffApplicationCommands(registered, generated)
+	if o.DryRun || diff.IsEmpty() {
+		return diff, nil
+	}
+
+	if o.GuildID.IsValid() {
+		_, err = c.BulkOverwriteGuildCommands(app.ID, o.GuildID, generated)
+	} else {
+		_, err = c.BulkOverwriteCommands(app.ID, generated)
+	}
+
+	return diff, errors.WithStack(err)
+}
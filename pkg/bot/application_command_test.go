@@ -0,0 +1,422 @@
+package bot
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/i18n"
+	"github.com/mavolin/adam/pkg/impl/arg"
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+func TestArgOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, argOptions(i18n.NewFallbackLocalizer(), nil))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &arg.Config{
+			RequiredArgs: []arg.RequiredArg{
+				{Name: "Amount", Type: arg.IntegerWithBounds(1, 10), Description: "The amount."},
+			},
+			OptionalArgs: []arg.OptionalArg{
+				{Name: "member", Type: arg.Member, Description: "The member."},
+			},
+			Flags: []arg.Flag{
+				{Name: "silent", Type: arg.Switch, Description: "Whether to stay silent."},
+				{
+					Name: "color",
+					Type: arg.Choice{{Name: "red"}, {Name: "blue"}},
+				},
+			},
+		}
+
+		expect := []discord.CommandOption{
+			{
+				Type:        discord.IntegerOption,
+				Name:        "amount",
+				Description: "The amount.",
+				Required:    true,
+			},
+			{
+				Type:        discord.UserOption,
+				Name:        "member",
+				Description: "The member.",
+			},
+			{
+				Type:        discord.BooleanOption,
+				Name:        "silent",
+				Description: "Whether to stay silent.",
+			},
+			{
+				Type:        discord.StringOption,
+				Name:        "color",
+				Description: arg.Choice{}.GetName(i18n.NewFallbackLocalizer()),
+				Choices: []discord.CommandOptionChoice{
+					{Name: "red", Value: "red"},
+					{Name: "blue", Value: "blue"},
+				},
+			},
+		}
+
+		actual := argOptions(i18n.NewFallbackLocalizer(), cfg)
+		assert.Equal(t, expect, actual)
+	})
+}
+
+func TestTypeOption(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		typ    plugin.ArgType
+		expect discord.CommandOption
+	}{
+		{name: "role", typ: arg.Role, expect: discord.CommandOption{Type: discord.RoleOption}},
+		{
+			name: "text channel",
+			typ:  arg.TextChannel,
+			expect: discord.CommandOption{
+				Type:         discord.ChannelOption,
+				ChannelTypes: []discord.ChannelType{discord.GuildText, discord.GuildNews},
+			},
+		},
+		{name: "decimal", typ: arg.SimpleDecimal, expect: discord.CommandOption{Type: discord.NumberOption}},
+		{name: "unknown", typ: arg.SimpleText, expect: discord.CommandOption{Type: discord.StringOption}},
+		{
+			name:   "too many choices",
+			typ:    make(arg.Choice, maxChoices+1),
+			expect: discord.CommandOption{Type: discord.StringOption, Autocomplete: true},
+		},
+		{name: "completing user", typ: arg.User, expect: discord.CommandOption{Type: discord.UserOption}},
+		{name: "command", typ: arg.Command, expect: discord.CommandOption{Type: discord.StringOption, Autocomplete: true}},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := typeOption(i18n.NewFallbackLocalizer(), c.typ)
+			assert.Equal(t, c.expect, actual)
+		})
+	}
+}
+
+func TestApplicationCommandDescription(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fallback", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "abc", applicationCommandDescription("", "abc"))
+	})
+
+	t.Run("shortened", func(t *testing.T) {
+		t.Parallel()
+
+		actual := applicationCommandDescription(strings.Repeat("a", 200), "")
+		assert.Equal(t, strings.Repeat("a", maxDescriptionLength-3)+"...", actual)
+	})
+
+	t.Run("multi-byte", func(t *testing.T) {
+		t.Parallel()
+
+		actual := applicationCommandDescription(strings.Repeat("ä", maxDescriptionLength), "")
+		assert.Equal(t, strings.Repeat("ä", maxDescriptionLength), actual)
+
+		actual = applicationCommandDescription(strings.Repeat("ä", 200), "")
+		assert.Equal(t, strings.Repeat("ä", maxDescriptionLength-3)+"...", actual)
+	})
+}
+
+func TestDiffApplicationCommands(t *testing.T) {
+	t.Parallel()
+
+	registered := []discord.Command{
+		{ID: 1, Name: "abc", Description: "abc"},
+		{ID: 2, Name: "def", Description: "def"},
+		{ID: 3, Name: "ghi", Description: "ghi"},
+	}
+
+	generated := []discord.Command{
+		{Name: "abc", Description: "abc"},
+		{Name: "def", Description: "new def"},
+		{Name: "jkl", Description: "jkl"},
+	}
+
+	expect := &ApplicationCommandDiff{
+		Create: []discord.Command{{Name: "jkl", Description: "jkl"}},
+		Update: []discord.Command{{ID: 2, Name: "def", Description: "new def"}},
+		Delete: []discord.Command{{ID: 3, Name: "ghi", Description: "ghi"}},
+	}
+
+	actual := DiffApplicationCommands(registered, generated)
+	assert.Equal(t, expect, actual)
+	assert.False(t, actual.IsEmpty())
+	assert.Equal(t, "+ jkl\n~ def\n- ghi\n", actual.String())
+
+	t.Run("empty slices", func(t *testing.T) {
+		t.Parallel()
+
+		registered := []discord.Command{
+			{
+				ID:   1,
+				Name: "abc",
+				Options: []discord.CommandOption{
+					{Type: discord.SubcommandOption, Name: "def"},
+				},
+			},
+		}
+
+		generated := []discord.Command{
+			{
+				Name: "abc",
+				Options: []discord.CommandOption{
+					{
+						Type:    discord.SubcommandOption,
+						Name:    "def",
+						Options: []discord.CommandOption{},
+					},
+				},
+			},
+		}
+
+		actual := DiffApplicationCommands(registered, generated)
+		assert.True(t, actual.IsEmpty())
+	})
+}
+
+func TestValidateApplicationCommands(t *testing.T) {
+	t.Parallel()
+
+	tooManyCommands := make([]discord.Command, maxCommands+1)
+	for i := range tooManyCommands {
+		tooManyCommands[i] = discord.Command{Name: "cmd" + strconv.Itoa(i)}
+	}
+
+	tooManyOptions := make([]discord.CommandOption, maxOptions+1)
+	for i := range tooManyOptions {
+		tooManyOptions[i] = discord.CommandOption{Type: discord.BooleanOption, Name: "flag" + strconv.Itoa(i)}
+	}
+
+	testCases := []struct {
+		name  string
+		cmds  []discord.Command
+		valid bool
+	}{
+		{
+			name: "valid",
+			cmds: []discord.Command{
+				{Name: "abc", Options: []discord.CommandOption{{Name: "def"}, {Name: "ghi"}}},
+				{Name: "def", Options: []discord.CommandOption{{Name: "def"}}},
+			},
+			valid: true,
+		},
+		{name: "too many commands", cmds: tooManyCommands},
+		{name: "duplicate command", cmds: []discord.Command{{Name: "abc"}, {Name: "abc"}}},
+		{name: "too many options", cmds: []discord.Command{{Name: "abc", Options: tooManyOptions}}},
+		{
+			name: "duplicate option",
+			cmds: []discord.Command{
+				{Name: "abc", Options: []discord.CommandOption{{Name: "def"}, {Name: "def"}}},
+			},
+		},
+		{
+			name: "sub-command",
+			cmds: []discord.Command{
+				{
+					Name: "abc",
+					Options: []discord.CommandOption{
+						{
+							Type:    discord.SubcommandOption,
+							Name:    "def",
+							Options: []discord.CommandOption{{Name: "ghi"}, {Name: "ghi"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateApplicationCommands(c.cmds)
+			if c.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+
+	t.Run("arg and flag", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &arg.Config{
+			RequiredArgs: []arg.RequiredArg{{Name: "Silent", Type: arg.SimpleText}},
+			Flags:        []arg.Flag{{Name: "silent", Type: arg.Switch}},
+		}
+
+		cmds := []discord.Command{{Name: "abc", Options: argOptions(i18n.NewFallbackLocalizer(), cfg)}}
+
+		assert.Error(t, ValidateApplicationCommands(cmds))
+	})
+}
+
+// mockApplicationCommandClient is an applicationCommandClient that keeps the
+// registered commands in memory.
+type mockApplicationCommandClient struct {
+	appID discord.AppID
+
+	commands      []discord.Command
+	guildCommands map[discord.GuildID][]discord.Command
+
+	// overwrites is the number of bulk-overwrites.
+	overwrites int
+}
+
+func (c *mockApplicationCommandClient) CurrentApplication() (*discord.Application, error) {
+	return &discord.Application{ID: c.appID}, nil
+}
+
+func (c *mockApplicationCommandClient) Commands(appID discord.AppID) ([]discord.Command, error) {
+	if appID != c.appID {
+		return nil, errors.New("unknown application")
+	}
+
+	return c.commands, nil
+}
+
+func (c *mockApplicationCommandClient) GuildCommands(
+	appID discord.AppID, guildID discord.GuildID,
+) ([]discord.Command, error) {
+	if appID != c.appID {
+		return nil, errors.New("unknown application")
+	}
+
+	return c.guildCommands[guildID], nil
+}
+
+func (c *mockApplicationCommandClient) BulkOverwriteCommands(
+	appID discord.AppID, cmds []discord.Command,
+) ([]discord.Command, error) {
+	if appID != c.appID {
+		return nil, errors.New("unknown application")
+	}
+
+	c.overwrites++
+	c.commands = registerCommands(cmds)
+
+	return c.commands, nil
+}
+
+func (c *mockApplicationCommandClient) BulkOverwriteGuildCommands(
+	appID discord.AppID, guildID discord.GuildID, cmds []discord.Command,
+) ([]discord.Command, error) {
+	if appID != c.appID {
+		return nil, errors.New("unknown application")
+	}
+
+	c.overwrites++
+	c.guildCommands[guildID] = registerCommands(cmds)
+
+	return c.guildCommands[guildID], nil
+}
+
+// registerCommands returns a copy of the passed commands with ids set, as if
+// registered by Discord.
+func registerCommands(cmds []discord.Command) []discord.Command {
+	registered := make([]discord.Command, len(cmds))
+	for i, cmd := range cmds {
+		cmd.ID = discord.CommandID(i + 1)
+		registered[i] = cmd
+	}
+
+	return registered
+}
+
+func TestSyncApplicationCommands(t *testing.T) {
+	t.Parallel()
+
+	generated := []discord.Command{
+		{Name: "abc", Description: "abc"},
+		{Name: "def", Description: "def"},
+	}
+
+	t.Run("global", func(t *testing.T) {
+		t.Parallel()
+
+		c := &mockApplicationCommandClient{appID: 123}
+
+		diff, err := syncApplicationCommands(c, generated, SyncApplicationCommandsOptions{})
+		require.NoError(t, err)
+
+		assert.Equal(t, generated, diff.Create)
+		assert.Equal(t, 1, c.overwrites)
+		assert.Equal(t, registerCommands(generated), c.commands)
+
+		// nothing changed, so there should be no second overwrite
+		diff, err = syncApplicationCommands(c, generated, SyncApplicationCommandsOptions{})
+		require.NoError(t, err)
+
+		assert.True(t, diff.IsEmpty())
+		assert.Equal(t, 1, c.overwrites)
+	})
+
+	t.Run("guild", func(t *testing.T) {
+		t.Parallel()
+
+		c := &mockApplicationCommandClient{
+			appID:         123,
+			guildCommands: map[discord.GuildID][]discord.Command{456: {{ID: 1, Name: "ghi", Description: "ghi"}}},
+		}
+
+		diff, err := syncApplicationCommands(c, generated, SyncApplicationCommandsOptions{GuildID: 456})
+		require.NoError(t, err)
+
+		assert.Equal(t, generated, diff.Create)
+		assert.Equal(t, []discord.Command{{ID: 1, Name: "ghi", Description: "ghi"}}, diff.Delete)
+		assert.Equal(t, registerCommands(generated), c.guildCommands[456])
+		assert.Empty(t, c.commands)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		t.Parallel()
+
+		c := &mockApplicationCommandClient{appID: 123}
+
+		diff, err := syncApplicationCommands(c, generated, SyncApplicationCommandsOptions{DryRun: true})
+		require.NoError(t, err)
+
+		assert.Equal(t, generated, diff.Create)
+		assert.Zero(t, c.overwrites)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		c := &mockApplicationCommandClient{appID: 123}
+
+		invalid := []discord.Command{{Name: "abc"}, {Name: "abc"}}
+
+		_, err := syncApplicationCommands(c, invalid, SyncApplicationCommandsOptions{})
+		assert.Error(t, err)
+		assert.Zero(t, c.overwrites)
+	})
+}
@@ -3,6 +3,8 @@ package bot
 
 import (
 	"context"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/diamondburned/arikawa/v3/discord"
@@ -13,7 +15,9 @@ import (
 	"github.com/mavolin/disstate/v4/pkg/state"
 
 	"github.com/mavolin/adam/internal/resolved"
+	"github.com/mavolin/adam/pkg/impl/throttler"
 	"github.com/mavolin/adam/pkg/plugin"
+	"github.com/mavolin/adam/pkg/utils/logutil"
 )
 
 // Bot is the bot executing all commands.
@@ -27,17 +31,47 @@ type Bot struct {
 
 	selfID discord.UserID
 
+	// ctx is the parent context of all invokes.
+	// It is canceled when the bot is closed.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// invokeMutex secures closed, idle, and inFlight.
+	invokeMutex sync.Mutex
+	// closed specifies whether the bot was closed and therefore stopped
+	// accepting new invokes.
+	closed bool
+	// idle is closed, once the bot was closed and no invokes are in progress
+	// anymore.
+	idle chan struct{}
+	// inFlight maps the contexts of the invokes in progress to the ids of
+	// their commands.
+	// The id is empty, as long as the command wasn't found yet.
+	inFlight map[*plugin.Context]plugin.ID
+
 	// ----- Settings -----
 
 	Owners []discord.UserID
 
+	RestrictionProvider plugin.RestrictionProvider
+
 	EditAge time.Duration
 
+	CloseGracePeriod time.Duration
+
+	JanitorInterval time.Duration
+	// janitorStarted specifies whether Open started throttler.DefaultJanitor.
+	janitorStarted bool
+
+	Logger logutil.Logger
+
+	TraceExporter TraceExporter
+
 	ErrorHandler func(error, *state.State, *plugin.Context)
 
-	PanicHandler             func(recovered interface{}, s *state.State, ctx *plugin.Context)
-	MessageCreateMiddlewares []interface{}
-	MessageUpdateMiddlewares []interface{}
+	PanicHandler                 func(recovered interface{}, s *state.State, ctx *plugin.Context)
+	MessageCreateMiddlewares     []interface{}
+	MessageUpdateMiddlewares     []interface{}
+	InteractionCreateMiddlewares []interface{}
 }
 
 // A PluginSourceFunc is the function used to retrieve additional plugins from
@@ -97,12 +131,23 @@ func New(o Options) (b *Bot, err error) {
 	b.selfID = self.ID
 
 	b.Owners = o.Owners
+	b.RestrictionProvider = o.RestrictionProvider
 	b.EditAge = o.EditAge
+	b.CloseGracePeriod = o.CloseGracePeriod
+	b.JanitorInterval = o.JanitorInterval
+	b.Logger = o.Logger
+	b.TraceExporter = o.TraceExporter
 	b.ErrorHandler = o.ErrorHandler
 	b.PanicHandler = o.PanicHandler
+	b.MessageCreateMiddlewares = o.MessageCreateMiddlewares
+	b.MessageUpdateMiddlewares = o.MessageUpdateMiddlewares
+	b.InteractionCreateMiddlewares = o.InteractionCreateMiddlewares
 
 	b.pluginResolver = resolved.NewPluginResolver(o.ArgParser)
 
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+	b.inFlight = make(map[*plugin.Context]plugin.ID)
+
 	if !o.NoDefaultMiddlewares {
 		b.AddMiddleware(CheckMessageType)
 
@@ -112,7 +157,17 @@ func New(o Options) (b *Bot, err error) {
 
 		b.AddMiddleware(NewSettingsRetriever(o.SettingsProvider))
 		b.AddMiddleware(CheckPrefix)
-		b.AddMiddleware(FindCommand)
+		if o.CommandSuggestions != nil {
+			b.AddMiddleware(NewSuggestingCommandFinder(*o.CommandSuggestions))
+		} else {
+			b.AddMiddleware(FindCommand)
+		}
+
+		if o.RateLimit != nil {
+			b.AddMiddleware(NewRateLimiter(*o.RateLimit))
+		}
+
+		b.AddMiddleware(ApplyTimeout)
 		b.AddMiddleware(CheckChannelTypes)
 		b.AddMiddleware(CheckBotPermissions)
 		b.AddMiddleware(NewThrottlerChecker(o.ThrottlerCancelChecker))
@@ -135,6 +190,13 @@ func New(o Options) (b *Bot, err error) {
 // Additionally, gateway.IntentGuilds will be added, if guild caching is
 // enabled.
 //
+// Unless disabled, Open also starts throttler.DefaultJanitor, which is
+// stopped again by Close.
+//
+// Besides messages, Open also routes application command interactions.
+// Use Bot.SyncApplicationCommands to register the bot's commands as
+// application commands.
+//
 // Refer to the doc of State.Open to understand how the timeout is applied.
 func (b *Bot) Open(timeout time.Duration) error {
 	if i := b.State.Gateway.Identifier.Intents; i == nil || i == option.ZeroUint {
@@ -160,21 +222,176 @@ func (b *Bot) Open(timeout time.Duration) error {
 		}, b.MessageUpdateMiddlewares...)
 	}
 
+	b.State.AddHandler(func(_ *state.State, e *event.InteractionCreate) {
+		b.RouteInteraction(e.Base, &e.InteractionEvent)
+	}, b.InteractionCreateMiddlewares...)
+
+	if b.JanitorInterval > 0 && !b.janitorStarted {
+		throttler.DefaultJanitor.Start(b.JanitorInterval)
+		b.janitorStarted = true
+	}
+
 	return b.State.Open(timeout)
 }
 
-// Close closes all gateways handled by the bot.
+// Close gracefully shuts down the bot and closes all gateways handled by it.
+//
+// First, the bot stops accepting new invokes, and waits for the invokes in
+// progress to return.
+// If the passed context expires before that, Close cancels the
+// context.Context of the remaining invokes, and waits at most the bot's
+// CloseGracePeriod for them to return.
+// Afterwards, the gateways are closed.
 //
 // If an error occurs, Close will attempt to close all remaining gateways
 // first, before returning. If multiple errors occur during that process, a
 // MultiError will be returned.
+// If the gateways were closed successfully, but some invokes are still in
+// progress, Close returns an *InFlightError.
 //
-// The passed context will only be checked while waiting for all event handlers
-// to finish.
 // Even if the context expires, Close guarantees that all gateways are closed,
 // except if errors occurred.
+// If the context already expired when closing the gateways, they are closed
+// using a new context with a timeout of 5 seconds instead.
 func (b *Bot) Close(ctx context.Context) error {
-	return b.State.Close(ctx)
+	b.awaitInvokes(ctx)
+	b.cancel()
+
+	if b.janitorStarted {
+		throttler.DefaultJanitor.Stop()
+		b.janitorStarted = false
+	}
+
+	// don't pass on the expired context, so that the gateways can still be
+	// closed gracefully
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(context.Background(), stateCloseTimeout)
+		defer cancel()
+	}
+
+	if err := b.State.Close(ctx); err != nil {
+		return err
+	}
+
+	return b.inFlightError()
+}
+
+// stateCloseTimeout is the timeout used to close the gateways, if the context
+// passed to Close already expired.
+const stateCloseTimeout = 5 * time.Second
+
+// awaitInvokes stops accepting new invokes, and waits for the invokes in
+// progress to return.
+// If the passed context expires before that, awaitInvokes cancels the
+// context.Context of the remaining invokes, and waits at most the bot's
+// CloseGracePeriod for them to return.
+func (b *Bot) awaitInvokes(ctx context.Context) {
+	b.invokeMutex.Lock()
+
+	b.closed = true
+
+	if b.idle == nil {
+		b.idle = make(chan struct{})
+
+		if len(b.inFlight) == 0 {
+			close(b.idle)
+		}
+	}
+
+	idle := b.idle
+
+	b.invokeMutex.Unlock()
+
+	select {
+	case <-idle:
+	case <-ctx.Done():
+		b.cancel()
+
+		t := time.NewTimer(b.CloseGracePeriod)
+		defer t.Stop()
+
+		select {
+		case <-idle:
+		case <-t.C:
+		}
+	}
+}
+
+// InFlight returns the number of invokes currently in progress.
+func (b *Bot) InFlight() int {
+	b.invokeMutex.Lock()
+	defer b.invokeMutex.Unlock()
+
+	return len(b.inFlight)
+}
+
+// startInvoke registers the invoke with the passed context as in progress.
+// If the bot is closed, startInvoke returns false, and the invoke must be
+// discarded.
+func (b *Bot) startInvoke(ctx *plugin.Context) bool {
+	b.invokeMutex.Lock()
+	defer b.invokeMutex.Unlock()
+
+	if b.closed {
+		return false
+	}
+
+	b.inFlight[ctx] = ""
+
+	return true
+}
+
+// setInvokedCommand records the id of the command of the invoke with the
+// passed context.
+// It must be called from the goroutine routing the invoke, after the
+// command was found.
+func (b *Bot) setInvokedCommand(ctx *plugin.Context) {
+	b.invokeMutex.Lock()
+	defer b.invokeMutex.Unlock()
+
+	if _, ok := b.inFlight[ctx]; ok {
+		b.inFlight[ctx] = ctx.InvokedCommand.ID()
+	}
+}
+
+// endInvoke marks the invoke with the passed context as finished.
+func (b *Bot) endInvoke(ctx *plugin.Context) {
+	b.invokeMutex.Lock()
+	defer b.invokeMutex.Unlock()
+
+	delete(b.inFlight, ctx)
+
+	// the bot is closed, and this was the last invoke in progress
+	if b.idle != nil && len(b.inFlight) == 0 {
+		close(b.idle)
+	}
+}
+
+// inFlightError returns an *InFlightError describing the invokes still in
+// progress, or nil if there are none.
+func (b *Bot) inFlightError() error {
+	b.invokeMutex.Lock()
+	defer b.invokeMutex.Unlock()
+
+	if len(b.inFlight) == 0 {
+		return nil
+	}
+
+	err := &InFlightError{Count: len(b.inFlight)}
+
+	for _, id := range b.inFlight {
+		if id != "" {
+			err.CommandIDs = append(err.CommandIDs, id)
+		}
+	}
+
+	sort.Slice(err.CommandIDs, func(i, j int) bool {
+		return err.CommandIDs[i] < err.CommandIDs[j]
+	})
+
+	return err
 }
 
 // AddIntents adds the passed gateway.Intents to the bot.
@@ -213,6 +430,8 @@ func (b *Bot) AddModule(mod plugin.Module) {
 //	• func(*state.State, *state.MessageCreateEvent) error
 //	• func(*state.State, *state.MessageUpdateEvent)
 //	• func(*state.State, *state.MessageUpdateEvent) error
+//	• func(*state.State, *state.InteractionCreateEvent)
+//	• func(*state.State, *state.InteractionCreateEvent) error
 //	• func(next CommandFunc) CommandFunc
 func (b *Bot) TryAddPostMiddleware(f interface{}) error {
 	return b.postMiddlewares.TryAddMiddleware(f)
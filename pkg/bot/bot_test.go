@@ -1,11 +1,14 @@
 package bot
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/mavolin/disstate/v4/pkg/event"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/mavolin/adam/internal/resolved"
 	"github.com/mavolin/adam/pkg/plugin"
@@ -50,3 +53,135 @@ func TestBot_AddPluginSource(t *testing.T) {
 		assert.Len(t, b.pluginResolver.CustomSources, 1)
 	})
 }
+
+func TestBot_InFlight(t *testing.T) {
+	t.Parallel()
+
+	b := &Bot{inFlight: make(map[*plugin.Context]plugin.ID)}
+
+	ctx1 := new(plugin.Context)
+	ctx2 := new(plugin.Context)
+
+	assert.True(t, b.startInvoke(ctx1))
+	assert.True(t, b.startInvoke(ctx2))
+	assert.Equal(t, 2, b.InFlight())
+
+	b.endInvoke(ctx1)
+	assert.Equal(t, 1, b.InFlight())
+
+	b.endInvoke(ctx2)
+	assert.Equal(t, 0, b.InFlight())
+	assert.NoError(t, b.inFlightError())
+}
+
+func TestBot_startInvoke(t *testing.T) {
+	t.Parallel()
+
+	b := &Bot{inFlight: make(map[*plugin.Context]plugin.ID), closed: true}
+
+	assert.False(t, b.startInvoke(new(plugin.Context)))
+	assert.Equal(t, 0, b.InFlight())
+}
+
+func TestBot_inFlightError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no command ids", func(t *testing.T) {
+		t.Parallel()
+
+		b := &Bot{inFlight: make(map[*plugin.Context]plugin.ID)}
+
+		require.True(t, b.startInvoke(new(plugin.Context)))
+
+		expect := &InFlightError{Count: 1}
+
+		actual := b.inFlightError()
+		assert.Equal(t, expect, actual)
+		assert.Equal(t, "bot: 1 invokes still in progress", actual.Error())
+	})
+
+	t.Run("command ids", func(t *testing.T) {
+		t.Parallel()
+
+		b := &Bot{inFlight: make(map[*plugin.Context]plugin.ID)}
+
+		ctx := &plugin.Context{InvokedCommand: mockResolvedCommand{id: ".abc"}}
+
+		require.True(t, b.startInvoke(ctx))
+		require.True(t, b.startInvoke(new(plugin.Context)))
+
+		b.setInvokedCommand(ctx)
+
+		expect := &InFlightError{Count: 2, CommandIDs: []plugin.ID{".abc"}}
+
+		actual := b.inFlightError()
+		assert.Equal(t, expect, actual)
+		assert.Equal(t, "bot: 2 invokes still in progress: .abc", actual.Error())
+	})
+}
+
+type mockResolvedCommand struct {
+	plugin.ResolvedCommand
+	id plugin.ID
+}
+
+func (cmd mockResolvedCommand) ID() plugin.ID { return cmd.id }
+
+func TestBot_awaitInvokes(t *testing.T) {
+	t.Parallel()
+
+	newBot := func() *Bot {
+		b := &Bot{inFlight: make(map[*plugin.Context]plugin.ID), CloseGracePeriod: 10 * time.Millisecond}
+		b.ctx, b.cancel = context.WithCancel(context.Background())
+
+		return b
+	}
+
+	t.Run("no invokes", func(t *testing.T) {
+		t.Parallel()
+
+		b := newBot()
+		b.awaitInvokes(context.Background())
+
+		assert.False(t, b.startInvoke(new(plugin.Context)))
+		assert.NoError(t, b.ctx.Err())
+	})
+
+	t.Run("invokes return", func(t *testing.T) {
+		t.Parallel()
+
+		b := newBot()
+
+		ctx := new(plugin.Context)
+		require.True(t, b.startInvoke(ctx))
+
+		go b.endInvoke(ctx)
+
+		b.awaitInvokes(context.Background())
+
+		assert.Equal(t, 0, b.InFlight())
+		assert.NoError(t, b.ctx.Err())
+	})
+
+	t.Run("grace period", func(t *testing.T) {
+		t.Parallel()
+
+		b := newBot()
+
+		ctx := new(plugin.Context)
+		require.True(t, b.startInvoke(ctx))
+
+		closeCtx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		b.awaitInvokes(closeCtx)
+
+		assert.Error(t, b.ctx.Err())
+		assert.Equal(t, 1, b.InFlight())
+
+		// ending the invoke after the grace period ran out must not block
+		// or panic
+		b.endInvoke(ctx)
+		assert.Equal(t, 0, b.InFlight())
+	})
+}
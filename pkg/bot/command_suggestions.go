@@ -0,0 +1,317 @@
+package bot
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/mavolin/disstate/v4/pkg/state"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/i18n"
+	"github.com/mavolin/adam/pkg/plugin"
+	"github.com/mavolin/adam/pkg/utils/msgbuilder"
+)
+
+// CommandSuggestions are the settings used to suggest commands and modules,
+// if a user invokes a command that does not exist.
+type CommandSuggestions struct {
+	// MaxSuggestions is the maximum number of plugins that are suggested.
+	//
+	// Default: 3
+	MaxSuggestions int
+	// MaxDistance is the maximum edit distance between the invoke used and
+	// the invoke of a suggested plugin.
+	// The edit distance is calculated for each word of the invoke
+	// separately, and the distances are then summed up.
+	//
+	// Default: 2
+	MaxDistance int
+
+	// InvokeButton specifies whether to add a button to the error message
+	// that invokes the closest command, if the closest match is a command.
+	// Clicking the button invokes the suggested command with the arguments
+	// used in the original invoke.
+	InvokeButton bool
+	// ButtonTimeout is the time the user has to click the invoke button.
+	//
+	// Default: 30 * time.Second
+	ButtonTimeout time.Duration
+}
+
+// commandSuggestion is a plugin suggested to the user.
+type commandSuggestion struct {
+	// invoke is the invoke of the plugin.
+	invoke string
+	// cmd is the suggested command, or nil if the suggestion is a module.
+	cmd plugin.ResolvedCommand
+	// numWords is the number of words of the original invoke used to find
+	// the suggestion.
+	numWords int
+	// distance is the edit distance between the original invoke and the
+	// suggestion.
+	distance int
+}
+
+// NewSuggestingCommandFinder creates a new Middleware that can be used
+// instead of FindCommand.
+// Like FindCommand it attempts to find the command being invoked, and sets the
+// InvokedCommand and ArgsIndex context fields.
+//
+// If no matching command is found, it searches the commands and modules
+// visible to the user, i.e. those that are neither hidden nor restricted, for
+// those whose names or aliases are similar to the invoke used.
+// If there are none, the middleware returns ErrUnknownCommand.
+// Otherwise, it returns a localized *errors.UserError listing the
+// suggestions, or, if cs.InvokeButton is true and the closest suggestion is
+// a command, sends that list itself, alongside a button to invoke the
+// closest command.
+func NewSuggestingCommandFinder(cs CommandSuggestions) Middleware {
+	if cs.MaxSuggestions <= 0 {
+		cs.MaxSuggestions = 3
+	}
+
+	if cs.MaxDistance <= 0 {
+		cs.MaxDistance = 2
+	}
+
+	if cs.ButtonTimeout <= 0 {
+		cs.ButtonTimeout = 30 * time.Second
+	}
+
+	return func(next CommandFunc) CommandFunc {
+		return func(s *state.State, ctx *plugin.Context) error {
+			cmd, rawArgs := ctx.FindCommandWithArgs(ctx.Content[ctx.InvokeIndex:])
+			if cmd != nil {
+				ctx.InvokedCommand = cmd
+				ctx.ArgsIndex = len(ctx.Content) - len(rawArgs)
+
+				return next(s, ctx)
+			}
+
+			words := strings.Fields(ctx.Content[ctx.InvokeIndex:])
+			if len(words) == 0 {
+				return ErrUnknownCommand
+			}
+
+			suggestions := suggestCommands(s, ctx, words, cs.MaxDistance)
+			if len(suggestions) == 0 {
+				return ErrUnknownCommand
+			}
+
+			if len(suggestions) > cs.MaxSuggestions {
+				suggestions = suggestions[:cs.MaxSuggestions]
+			}
+
+			desc := unknownCommandSuggestionsErrorDescription.
+				WithPlaceholders(unknownCommandSuggestionsErrorDescriptionPlaceholders{
+					Suggestions: joinSuggestions(suggestions),
+				})
+
+			if !cs.InvokeButton || suggestions[0].cmd == nil || ctx.IsInteraction() {
+				return errors.NewUserErrorl(desc)
+			}
+
+			run, err := sendSuggestionButton(s, ctx, desc, suggestions[0], cs.ButtonTimeout)
+			if err != nil {
+				var terr *msgbuilder.TimeoutError
+				if errors.As(err, &terr) {
+					return errors.Abort
+				}
+
+				return err
+			} else if !run {
+				return errors.Abort
+			}
+
+			// rewrite the invoke, so that the arguments are found at the
+			// correct position
+			rawArgs = ctx.Content[ctx.InvokeIndex:]
+			for _, word := range words[:suggestions[0].numWords] {
+				rawArgs = strings.TrimLeftFunc(rawArgs, unicode.IsSpace)[len(word):]
+			}
+
+			rawArgs = strings.TrimLeftFunc(rawArgs, unicode.IsSpace)
+
+			ctx.Content = ctx.Content[:ctx.InvokeIndex] + suggestions[0].invoke
+			if rawArgs != "" {
+				ctx.Content += " " + rawArgs
+			}
+
+			ctx.InvokedCommand = suggestions[0].cmd
+			ctx.ArgsIndex = len(ctx.Content) - len(rawArgs)
+
+			return next(s, ctx)
+		}
+	}
+}
+
+// sendSuggestionButton sends the passed description alongside a button that
+// invokes the passed suggestion, and waits for the user to click it.
+func sendSuggestionButton(
+	s *state.State, ctx *plugin.Context, desc *i18n.Config, suggestion commandSuggestion, timeout time.Duration,
+) (run bool, err error) {
+	label := unknownCommandInvokeButtonLabel.
+		WithPlaceholders(unknownCommandInvokeButtonLabelPlaceholders{
+			Invoke: suggestion.invoke,
+		})
+
+	_, err = msgbuilder.New(s, ctx).
+		WithContentl(desc).
+		WithAwaitedComponent(msgbuilder.NewActionRow(&run).
+			With(msgbuilder.NewButtonl(discord.PrimaryButton, label, true))).
+		ReplyAndAwait(timeout)
+
+	return run, err
+}
+
+// suggestCommands returns the commands and modules visible to the invoking
+// user, whose invoke is at most maxDistance away from the passed words.
+// The suggestions are sorted by distance and then by invoke.
+func suggestCommands(s *state.State, ctx *plugin.Context, words []string, maxDistance int) []commandSuggestion {
+	var suggestions []commandSuggestion
+
+	var walk func(cmds []plugin.ResolvedCommand, mods []plugin.ResolvedModule, depth, distance int)
+	walk = func(cmds []plugin.ResolvedCommand, mods []plugin.ResolvedModule, depth, distance int) {
+		if depth >= len(words) {
+			return
+		}
+
+		word := strings.ToLower(words[depth])
+
+		for _, cmd := range cmds {
+			d := distance + editDistance(word, strings.ToLower(cmd.Name()))
+			for _, alias := range cmd.Aliases() {
+				if ad := distance + editDistance(word, strings.ToLower(alias)); ad < d {
+					d = ad
+				}
+			}
+
+			if d > maxDistance || !isCommandVisible(s, ctx, cmd) {
+				continue
+			}
+
+			suggestions = append(suggestions, commandSuggestion{
+				invoke:   cmd.ID().AsInvoke(),
+				cmd:      cmd,
+				numWords: depth + 1,
+				distance: d,
+			})
+		}
+
+		for _, mod := range mods {
+			if mod.IsHidden() {
+				continue
+			}
+
+			d := distance + editDistance(word, strings.ToLower(mod.Name()))
+			if d > maxDistance {
+				continue
+			}
+
+			// an exact match of a module is not worth suggesting, but its
+			// commands might be
+			if d > 0 && isModuleVisible(s, ctx, mod) {
+				suggestions = append(suggestions, commandSuggestion{
+					invoke:   mod.ID().AsInvoke(),
+					numWords: depth + 1,
+					distance: d,
+				})
+			}
+
+			walk(mod.Commands(), mod.Modules(), depth+1, d)
+		}
+	}
+
+	walk(ctx.Commands(), ctx.Modules(), 0, 0)
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].distance != suggestions[j].distance {
+			return suggestions[i].distance < suggestions[j].distance
+		}
+
+		return suggestions[i].invoke < suggestions[j].invoke
+	})
+
+	return suggestions
+}
+
+// isCommandVisible checks if the passed command is neither hidden nor
+// restricted.
+func isCommandVisible(s *state.State, ctx *plugin.Context, cmd plugin.ResolvedCommand) bool {
+	return !cmd.IsHidden() && cmd.IsRestricted(s, ctx) == nil
+}
+
+// isModuleVisible checks if the passed module is not hidden and contains at
+// least one visible command.
+func isModuleVisible(s *state.State, ctx *plugin.Context, mod plugin.ResolvedModule) bool {
+	if mod.IsHidden() {
+		return false
+	}
+
+	for _, cmd := range mod.Commands() {
+		if isCommandVisible(s, ctx, cmd) {
+			return true
+		}
+	}
+
+	for _, smod := range mod.Modules() {
+		if isModuleVisible(s, ctx, smod) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// joinSuggestions joins the invokes of the passed suggestions in a
+// comma-separated list of inline code blocks.
+func joinSuggestions(suggestions []commandSuggestion) string {
+	invokes := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		invokes[i] = "`" + s.invoke + "`"
+	}
+
+	return strings.Join(invokes, ", ")
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+
+	prev := make([]int, len(br)+1)
+	cur := make([]int, len(br)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		cur[0] = i
+
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, cur = cur, prev
+	}
+
+	return prev[len(br)]
+}
+
+func minInt(a int, others ...int) int {
+	for _, b := range others {
+		if b < a {
+			a = b
+		}
+	}
+
+	return a
+}
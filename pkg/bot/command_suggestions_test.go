@@ -0,0 +1,156 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/mavolin/disstate/v4/pkg/state"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+type (
+	suggestionProvider struct {
+		plugin.Provider
+		cmds []plugin.ResolvedCommand
+		mods []plugin.ResolvedModule
+	}
+
+	suggestionCommand struct {
+		plugin.ResolvedCommand
+		id         plugin.ID
+		aliases    []string
+		hidden     bool
+		restricted bool
+	}
+
+	suggestionModule struct {
+		plugin.ResolvedModule
+		id     plugin.ID
+		cmds   []plugin.ResolvedCommand
+		mods   []plugin.ResolvedModule
+		hidden bool
+	}
+)
+
+func (p suggestionProvider) Commands() []plugin.ResolvedCommand { return p.cmds }
+func (p suggestionProvider) Modules() []plugin.ResolvedModule   { return p.mods }
+
+func (cmd suggestionCommand) ID() plugin.ID     { return cmd.id }
+func (cmd suggestionCommand) Name() string      { return cmd.id.Name() }
+func (cmd suggestionCommand) Aliases() []string { return cmd.aliases }
+func (cmd suggestionCommand) IsHidden() bool    { return cmd.hidden }
+func (cmd suggestionCommand) IsRestricted(*state.State, *plugin.Context) error {
+	if cmd.restricted {
+		return errors.New("restricted")
+	}
+
+	return nil
+}
+
+func (mod suggestionModule) ID() plugin.ID                      { return mod.id }
+func (mod suggestionModule) Name() string                       { return mod.id.Name() }
+func (mod suggestionModule) IsHidden() bool                     { return mod.hidden }
+func (mod suggestionModule) Commands() []plugin.ResolvedCommand { return mod.cmds }
+func (mod suggestionModule) Modules() []plugin.ResolvedModule   { return mod.mods }
+
+func TestSuggestCommands(t *testing.T) {
+	t.Parallel()
+
+	ping := suggestionCommand{id: ".ping", aliases: []string{"latency"}}
+	pong := suggestionCommand{id: ".pong"}
+	secret := suggestionCommand{id: ".pint", hidden: true}
+	admin := suggestionCommand{id: ".ping2", restricted: true}
+	modBan := suggestionCommand{id: ".mod.ban"}
+
+	mod := suggestionModule{id: ".mod", cmds: []plugin.ResolvedCommand{modBan}}
+
+	ctx := &plugin.Context{
+		Provider: suggestionProvider{
+			cmds: []plugin.ResolvedCommand{admin, ping, pong, secret},
+			mods: []plugin.ResolvedModule{mod},
+		},
+	}
+
+	testCases := []struct {
+		name   string
+		words  []string
+		expect []commandSuggestion
+	}{
+		{
+			name:  "name",
+			words: []string{"pnig"},
+			expect: []commandSuggestion{
+				{invoke: "ping", cmd: ping, numWords: 1, distance: 2},
+				{invoke: "pong", cmd: pong, numWords: 1, distance: 2},
+			},
+		},
+		{
+			name:  "alias",
+			words: []string{"latenc", "abc"},
+			expect: []commandSuggestion{
+				{invoke: "ping", cmd: ping, numWords: 1, distance: 1},
+			},
+		},
+		{
+			name:  "case insensitive",
+			words: []string{"PONG"},
+			expect: []commandSuggestion{
+				{invoke: "pong", cmd: pong, numWords: 1, distance: 0},
+				{invoke: "ping", cmd: ping, numWords: 1, distance: 1},
+			},
+		},
+		{
+			name:  "sub command",
+			words: []string{"mod", "bam"},
+			expect: []commandSuggestion{
+				{invoke: "mod ban", cmd: modBan, numWords: 2, distance: 1},
+			},
+		},
+		{
+			name:  "module",
+			words: []string{"mdo"},
+			expect: []commandSuggestion{
+				{invoke: "mod", numWords: 1, distance: 2},
+			},
+		},
+		{name: "none", words: []string{"abcdef"}},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := suggestCommands(nil, ctx, c.words, 2)
+			assert.Equal(t, c.expect, actual)
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		a, b   string
+		expect int
+	}{
+		{a: "", b: "", expect: 0},
+		{a: "abc", b: "", expect: 3},
+		{a: "", b: "abc", expect: 3},
+		{a: "abc", b: "abc", expect: 0},
+		{a: "kitten", b: "sitting", expect: 3},
+		{a: "help", b: "hlep", expect: 2},
+		{a: "äöü", b: "aöü", expect: 1},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.a+"-"+c.b, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, c.expect, editDistance(c.a, c.b))
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package bot
 
 import (
+	"context"
 	"regexp"
 	"strings"
 	"sync"
@@ -118,12 +119,18 @@ func NewSettingsRetriever(settingsProvider SettingsProvider) Middleware {
 //
 // If the prefix doesn't match, an *errors.InformationalError will be returned.
 //
+// Application commands are not prefixed, and are therefore always passed on.
+//
 // The middleware sets the ctx.InvokeIndex context field.
 func CheckPrefix(next CommandFunc) CommandFunc {
 	var selfMentionRegexp *regexp.Regexp
 	var once sync.Once
 
 	return func(s *state.State, ctx *plugin.Context) (err error) {
+		if ctx.IsInteraction() {
+			return next(s, ctx)
+		}
+
 		once.Do(func() {
 			var self *discord.User
 
@@ -179,6 +186,31 @@ func FindCommand(next CommandFunc) CommandFunc {
 	}
 }
 
+// ApplyTimeout applies the timeout of the invoked command, if it implements
+// plugin.Timeouter, to ctx.Ctx.
+//
+// The middleware replaces the Ctx context field.
+func ApplyTimeout(next CommandFunc) CommandFunc {
+	return func(s *state.State, ctx *plugin.Context) error {
+		t, ok := ctx.InvokedCommand.Source().(plugin.Timeouter)
+		if !ok {
+			return next(s, ctx)
+		}
+
+		timeout := t.GetTimeout()
+		if timeout <= 0 {
+			return next(s, ctx)
+		}
+
+		var cancel context.CancelFunc
+
+		ctx.Ctx, cancel = context.WithTimeout(ctx.Ctx, timeout)
+		defer cancel()
+
+		return next(s, ctx)
+	}
+}
+
 // CheckChannelTypes checks if the plugin.ChannelTypes of the command are
 // satisfied.
 func CheckChannelTypes(next CommandFunc) CommandFunc {
@@ -222,19 +254,26 @@ func CheckBotPermissions(next CommandFunc) CommandFunc {
 
 // NewThrottlerChecker creates a new bot.Middleware that checks if a
 // command is being throttled.
-// Additionally, it signals cancellation to the throttler.
+// Additionally, it signals cancellation to the throttler, and, if the
+// throttler is a plugin.ReleasingThrottler, releases the invoke after the
+// command returned.
 func NewThrottlerChecker(cancelChecker func(err error) bool) Middleware {
 	return func(next CommandFunc) CommandFunc {
 		return func(s *state.State, ctx *plugin.Context) error {
-			if ctx.InvokedCommand.Throttler() == nil {
+			t := ctx.InvokedCommand.Throttler()
+			if t == nil {
 				return next(s, ctx)
 			}
 
-			rm, err := ctx.InvokedCommand.Throttler().Check(s, ctx)
+			rm, err := t.Check(s, ctx)
 			if err != nil {
 				return err
 			}
 
+			if rt, ok := t.(plugin.ReleasingThrottler); ok {
+				defer rt.Release(ctx)
+			}
+
 			panicked := true
 
 			// hacky way to check if we panicked, without repanicking and
@@ -270,9 +309,17 @@ func CheckRestrictions(next CommandFunc) CommandFunc {
 }
 
 // ParseArgs parses the ctx.RawArgs using the commands plugin.ArgConfig.
+//
+// If the command was invoked through an application command, the options of
+// the interaction are parsed instead, using the types of the arguments and
+// flags they represent.
 func ParseArgs(next CommandFunc) CommandFunc {
 	return func(s *state.State, ctx *plugin.Context) (err error) {
-		if ctx.InvokedCommand.Args() != nil {
+		if ctx.IsInteraction() {
+			if err = parseInteractionArgs(s, ctx); err != nil {
+				return err
+			}
+		} else if ctx.InvokedCommand.Args() != nil {
 			err = ctx.InvokedCommand.ArgParser().
 				Parse(ctx.RawArgs(), ctx.InvokedCommand.Args(), s, ctx)
 			if err != nil {
@@ -0,0 +1,148 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mavolin/disstate/v4/pkg/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+type (
+	timeoutCommand struct {
+		plugin.ResolvedCommand
+		source plugin.Command
+	}
+
+	timeoutSource struct {
+		plugin.Command
+		timeout time.Duration
+	}
+)
+
+func (cmd timeoutCommand) Source() plugin.Command { return cmd.source }
+
+func (cmd timeoutSource) GetTimeout() time.Duration { return cmd.timeout }
+
+func TestApplyTimeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no timeouter", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := &plugin.Context{
+			InvokedCommand: timeoutCommand{source: struct{ plugin.Command }{}},
+			Ctx:            context.Background(),
+		}
+
+		err := ApplyTimeout(func(_ *state.State, ctx *plugin.Context) error {
+			_, ok := ctx.Ctx.Deadline()
+			assert.False(t, ok)
+
+			return nil
+		})(nil, ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("no timeout", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := &plugin.Context{
+			InvokedCommand: timeoutCommand{source: timeoutSource{}},
+			Ctx:            context.Background(),
+		}
+
+		err := ApplyTimeout(func(_ *state.State, ctx *plugin.Context) error {
+			_, ok := ctx.Ctx.Deadline()
+			assert.False(t, ok)
+
+			return nil
+		})(nil, ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := &plugin.Context{
+			InvokedCommand: timeoutCommand{source: timeoutSource{timeout: time.Hour}},
+			Ctx:            context.Background(),
+		}
+
+		var invokeCtx context.Context
+
+		err := ApplyTimeout(func(_ *state.State, ctx *plugin.Context) error {
+			invokeCtx = ctx.Ctx
+
+			deadline, ok := ctx.Ctx.Deadline()
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(time.Hour), deadline, time.Minute)
+
+			return nil
+		})(nil, ctx)
+		assert.NoError(t, err)
+
+		assert.Error(t, invokeCtx.Err(), "context was not canceled after returning")
+	})
+}
+
+type throttledCommand struct {
+	plugin.ResolvedCommand
+	throttler plugin.Throttler
+}
+
+func (cmd throttledCommand) Throttler() plugin.Throttler { return cmd.throttler }
+
+type releasingThrottler struct {
+	checked, released bool
+}
+
+func (t *releasingThrottler) Check(*state.State, *plugin.Context) (func(), error) {
+	t.checked = true
+	return func() {}, nil
+}
+
+func (t *releasingThrottler) Release(*plugin.Context) { t.released = true }
+
+func TestNewThrottlerChecker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("release", func(t *testing.T) {
+		t.Parallel()
+
+		throttler := new(releasingThrottler)
+
+		ctx := &plugin.Context{InvokedCommand: throttledCommand{throttler: throttler}}
+
+		f := NewThrottlerChecker(func(error) bool { return false })
+		err := f(func(*state.State, *plugin.Context) error {
+			assert.False(t, throttler.released, "throttler was released before the command returned")
+			return nil
+		})(nil, ctx)
+		require.NoError(t, err)
+
+		assert.True(t, throttler.checked)
+		assert.True(t, throttler.released)
+	})
+
+	t.Run("release on error", func(t *testing.T) {
+		t.Parallel()
+
+		throttler := new(releasingThrottler)
+
+		ctx := &plugin.Context{InvokedCommand: throttledCommand{throttler: throttler}}
+
+		f := NewThrottlerChecker(func(error) bool { return false })
+		err := f(func(*state.State, *plugin.Context) error {
+			return errors.New("abc")
+		})(nil, ctx)
+		require.Error(t, err)
+
+		assert.True(t, throttler.released)
+	})
+}
@@ -1,6 +1,11 @@
 package bot
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mavolin/adam/pkg/plugin"
+)
 
 // ReplyTypeError is the error used if a reply returned by
 // plugin.Command.Invoke is not of a supported types.
@@ -11,3 +16,26 @@ type ReplyTypeError struct {
 func (r *ReplyTypeError) Error() string {
 	return fmt.Sprintf("bot: cannot use %T as type for reply", r.Reply)
 }
+
+// InFlightError is the error returned by Bot.Close, if some invokes were still
+// in progress when the bot finished closing.
+type InFlightError struct {
+	// Count is the number of invokes still in progress.
+	Count int
+	// CommandIDs are the ids of the commands still running.
+	// Invokes whose command was not yet determined are not included.
+	CommandIDs []plugin.ID
+}
+
+func (e *InFlightError) Error() string {
+	if len(e.CommandIDs) == 0 {
+		return fmt.Sprintf("bot: %d invokes still in progress", e.Count)
+	}
+
+	ids := make([]string, len(e.CommandIDs))
+	for i, id := range e.CommandIDs {
+		ids[i] = string(id)
+	}
+
+	return fmt.Sprintf("bot: %d invokes still in progress: %s", e.Count, strings.Join(ids, ", "))
+}
@@ -0,0 +1,499 @@
+package bot
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/mavolin/disstate/v4/pkg/event"
+	"github.com/mavolin/disstate/v4/pkg/state"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/i18n"
+	"github.com/mavolin/adam/pkg/impl/arg"
+	"github.com/mavolin/adam/pkg/impl/replier"
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+var interfaceType = reflect.TypeOf(func(interface{}) {}).In(0)
+
+// RouteInteraction attempts to route the passed interaction.
+// All interactions that are neither application commands nor autocomplete
+// interactions are ignored.
+//
+// The plugin.Context is created the same way Route creates it, with the
+// difference that the context's Message is synthesized from the interaction.
+// The Content of that message is the invoke of the command, i.e. the name of
+// the command prefixed by the names of the sub-command groups and
+// sub-commands that were used.
+//
+// When calling the bot's middlewares, RouteInteraction guarantees the same
+// fields to be set as Route.
+// Additionally, Interaction will be set, and Replier will be a
+// *replier.InteractionReplier.
+//
+// Autocomplete interactions are answered with the Completions of the
+// plugin.CompletingArgType of the focused option, without invoking any
+// middlewares.
+func (b *Bot) RouteInteraction(base *event.Base, e *discord.InteractionEvent) {
+	if e.Data == nil {
+		return
+	}
+
+	switch e.Data.Type() {
+	case discord.CommandInteraction:
+		data := e.Data.(*discord.CommandInteractionData)
+
+		ctx := b.newInteractionContext(base, e, interactionInvoke(data))
+		if ctx == nil {
+			return
+		}
+
+		b.route(ctx)
+	case discord.AutocompleteInteraction:
+		b.routeAutocomplete(base, e)
+	}
+}
+
+// newInteractionContext creates a new *plugin.Context for the passed
+// interaction, using the passed invoke as content.
+// It returns nil, if the interaction has no author.
+func (b *Bot) newInteractionContext(base *event.Base, e *discord.InteractionEvent, invoke string) *plugin.Context {
+	msg := &discord.Message{
+		ChannelID: e.ChannelID,
+		GuildID:   e.GuildID,
+		Type:      discord.DefaultMessage,
+		Timestamp: discord.NewTimestamp(e.ID.Time()),
+		Content:   invoke,
+	}
+
+	switch {
+	case e.Member != nil:
+		msg.Author = e.Member.User
+	case e.User != nil:
+		msg.Author = *e.User
+	default:
+		return nil
+	}
+
+	ctx := b.newContext(base, msg, e.Member)
+	ctx.Interaction = e
+	ctx.Replier = replier.WrapInteraction(b.State, e, false)
+	ctx.ArgsIndex = len(ctx.Content)
+
+	return ctx
+}
+
+// interactionInvoke returns the invoke of the command described by the passed
+// data.
+func interactionInvoke(data *discord.CommandInteractionData) string {
+	var b strings.Builder
+	b.WriteString(data.Name)
+
+	opts := data.Options
+
+	for len(opts) == 1 && isSubcommandOption(opts[0]) {
+		b.WriteRune(' ')
+		b.WriteString(opts[0].Name)
+
+		opts = opts[0].Options
+	}
+
+	return b.String()
+}
+
+// interactionArgOptions returns the options of the passed data that represent
+// arguments and flags.
+func interactionArgOptions(data *discord.CommandInteractionData) []discord.CommandInteractionOption {
+	opts := data.Options
+
+	for len(opts) == 1 && isSubcommandOption(opts[0]) {
+		opts = opts[0].Options
+	}
+
+	return opts
+}
+
+// isSubcommandOption checks if the passed option is a sub-command or a
+// sub-command group.
+// Only those options don't have a value.
+func isSubcommandOption(opt discord.CommandInteractionOption) bool {
+	return len(opt.Value) == 0 || string(opt.Value) == "null"
+}
+
+// applicationCommandName returns the name of the application command or
+// option representing the plugin, argument, or flag with the passed name.
+func applicationCommandName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), " ", "-")
+}
+
+// =============================================================================
+// Autocomplete
+// =====================================================================================
+
+// routeAutocomplete answers the passed autocomplete interaction with the
+// Completions of the focused option.
+// If the focused option's type is not a plugin.CompletingArgType, or if
+// there are no matching Completions, no choices are sent.
+func (b *Bot) routeAutocomplete(base *event.Base, e *discord.InteractionEvent) {
+	data := e.Data.(*discord.AutocompleteInteractionData)
+
+	ctx := b.newInteractionContext(base, e, autocompleteInvoke(data))
+	if ctx == nil {
+		return
+	}
+
+	ctx.InvokedCommand = ctx.FindCommand(ctx.Content)
+	if ctx.InvokedCommand == nil {
+		return
+	}
+
+	completions, err := completeInteractionOption(b.State, ctx, autocompleteArgOptions(data))
+	if err != nil {
+		b.ErrorHandler(err, b.State, ctx)
+		return
+	}
+
+	choices := make([]api.AutocompleteChoice, len(completions))
+	for i, c := range completions {
+		choices[i] = api.AutocompleteChoice{Name: c.Name, Value: c.Value}
+	}
+
+	err = b.State.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+		Type: api.AutocompleteResult,
+		Data: &api.InteractionResponseData{Choices: &choices},
+	})
+	if err != nil {
+		b.ErrorHandler(errors.WithStack(err), b.State, ctx)
+	}
+}
+
+// autocompleteInvoke returns the invoke of the command described by the
+// passed data.
+func autocompleteInvoke(data *discord.AutocompleteInteractionData) string {
+	var b strings.Builder
+	b.WriteString(data.Name)
+
+	opts := data.Options
+
+	for len(opts) == 1 && isSubcommandAutocompleteOption(opts[0]) {
+		b.WriteRune(' ')
+		b.WriteString(opts[0].Name)
+
+		opts = opts[0].Options
+	}
+
+	return b.String()
+}
+
+// autocompleteArgOptions returns the options of the passed data that
+// represent arguments and flags.
+func autocompleteArgOptions(data *discord.AutocompleteInteractionData) []discord.AutocompleteOption {
+	opts := data.Options
+
+	for len(opts) == 1 && isSubcommandAutocompleteOption(opts[0]) {
+		opts = opts[0].Options
+	}
+
+	return opts
+}
+
+// isSubcommandAutocompleteOption checks if the passed option is a
+// sub-command or a sub-command group.
+func isSubcommandAutocompleteOption(opt discord.AutocompleteOption) bool {
+	return opt.Type == discord.SubcommandOption || opt.Type == discord.SubcommandGroupOption
+}
+
+// completeInteractionOption returns the Completions for the focused option
+// of the passed options, as returned by the plugin.CompletingArgType of the
+// argument or flag the option represents.
+// The context's InvokedCommand must be set.
+func completeInteractionOption(
+	s *state.State, ctx *plugin.Context, opts []discord.AutocompleteOption,
+) ([]plugin.Completion, error) {
+	var focused *discord.AutocompleteOption
+
+	for i := range opts {
+		if opts[i].Focused {
+			focused = &opts[i]
+			break
+		}
+	}
+
+	if focused == nil {
+		return nil, nil
+	}
+
+	cfg := ctx.InvokedCommand.Args()
+	if cfg == nil {
+		return nil, nil
+	}
+
+	pctx := &plugin.ParseContext{Context: ctx, Raw: focused.Value}
+
+	var typ plugin.ArgType
+
+	fallback := i18n.NewFallbackLocalizer()
+
+	rargs := cfg.GetRequiredArgs()
+
+	for i, a := range rargs {
+		if applicationCommandName(a.GetName(fallback)) == focused.Name {
+			typ = a.GetType()
+			pctx.Name = a.GetName(ctx.Localizer)
+			pctx.Index = i
+			pctx.Kind = plugin.KindArg
+		}
+	}
+
+	for i, a := range cfg.GetOptionalArgs() {
+		if applicationCommandName(a.GetName(fallback)) == focused.Name {
+			typ = a.GetType()
+			pctx.Name = a.GetName(ctx.Localizer)
+			pctx.Index = len(rargs) + i
+			pctx.Kind = plugin.KindArg
+		}
+	}
+
+	for _, f := range cfg.GetFlags() {
+		if applicationCommandName(f.GetName()) == focused.Name {
+			typ = f.GetType()
+			pctx.Name = "-" + f.GetName()
+			pctx.Kind = plugin.KindFlag
+		}
+	}
+
+	completingTyp, ok := typ.(plugin.CompletingArgType)
+	if !ok {
+		return nil, nil
+	}
+
+	pctx.UsedName = pctx.Name
+
+	return completingTyp.Complete(s, pctx, maxChoices)
+}
+
+// =============================================================================
+// Option Parsing
+// =====================================================================================
+
+// interactionArgParser parses the options of an application command
+// interaction into plugin.Args and plugin.Flags.
+//
+// Instead of relying on Discord's types, all options are parsed by the
+// plugin.ArgType of their argument or flag, just as if they were part of a
+// message.
+// That way all validation and all conversions made by the types remain
+// intact.
+type interactionArgParser struct {
+	s   *state.State
+	ctx *plugin.Context
+
+	// opts maps the application command names of the options to their raw
+	// values.
+	opts map[string]string
+
+	// fallback is the localizer used to derive the names of the options of
+	// the arguments.
+	fallback *i18n.Localizer
+}
+
+// parseInteractionArgs parses the options of the interaction found in the
+// passed context, and stores them in ctx.Args and ctx.Flags.
+func parseInteractionArgs(s *state.State, ctx *plugin.Context) error {
+	cfg := ctx.InvokedCommand.Args()
+	if cfg == nil {
+		return nil
+	}
+
+	data, ok := ctx.Interaction.Data.(*discord.CommandInteractionData)
+	if !ok {
+		return nil
+	}
+
+	opts := interactionArgOptions(data)
+
+	p := &interactionArgParser{
+		s:        s,
+		ctx:      ctx,
+		opts:     make(map[string]string, len(opts)),
+		fallback: i18n.NewFallbackLocalizer(),
+	}
+
+	for _, opt := range opts {
+		p.opts[opt.Name] = interactionOptionRaw(opt)
+	}
+
+	return p.parse(cfg)
+}
+
+// interactionOptionRaw returns the raw value of the passed option.
+// Strings are returned unquoted.
+func interactionOptionRaw(opt discord.CommandInteractionOption) string {
+	var s string
+	if err := json.Unmarshal(opt.Value, &s); err == nil {
+		return s
+	}
+
+	return string(opt.Value)
+}
+
+func (p *interactionArgParser) parse(cfg plugin.ArgConfig) error {
+	rargs := cfg.GetRequiredArgs()
+	oargs := cfg.GetOptionalArgs()
+
+	args := make(plugin.Args, 0, len(rargs)+len(oargs))
+
+	for i, a := range rargs {
+		variadic := cfg.IsVariadic() && len(oargs) == 0 && i == len(rargs)-1
+
+		raw, ok := p.opts[applicationCommandName(a.GetName(p.fallback))]
+		if !ok {
+			return plugin.NewArgumentErrorl(missingOptionError.
+				WithPlaceholders(missingOptionErrorPlaceholders{
+					Name: a.GetName(p.ctx.Localizer),
+				}))
+		}
+
+		val, err := p.parseArg(a.GetName(p.ctx.Localizer), a.GetType(), raw, i)
+		if err != nil {
+			return err
+		}
+
+		args = append(args, wrapIf(variadic, val))
+	}
+
+	for i, a := range oargs {
+		variadic := cfg.IsVariadic() && i == len(oargs)-1
+
+		raw, ok := p.opts[applicationCommandName(a.GetName(p.fallback))]
+		if !ok {
+			args = append(args, argDefault(a.GetDefault(), a.GetType(), variadic))
+			continue
+		}
+
+		val, err := p.parseArg(a.GetName(p.ctx.Localizer), a.GetType(), raw, len(rargs)+i)
+		if err != nil {
+			return err
+		}
+
+		args = append(args, wrapIf(variadic, val))
+	}
+
+	flags := make(plugin.Flags, len(cfg.GetFlags()))
+	usedFlags := make(map[string]string, len(cfg.GetFlags()))
+
+	for _, f := range cfg.GetFlags() {
+		raw, ok := p.opts[applicationCommandName(f.GetName())]
+		if !ok {
+			flags[f.GetName()] = argDefault(f.GetDefault(), f.GetType(), f.IsMulti())
+			continue
+		}
+
+		usedFlags[f.GetName()] = f.GetName()
+
+		val, err := p.parseFlag(f, raw)
+		if err != nil {
+			return err
+		}
+
+		flags[f.GetName()] = wrapIf(f.IsMulti(), val)
+	}
+
+	if err := arg.CheckFlagConstraints(cfg, usedFlags); err != nil {
+		return err
+	}
+
+	p.ctx.Args = args
+	p.ctx.Flags = flags
+
+	return nil
+}
+
+func (p *interactionArgParser) parseArg(name string, typ plugin.ArgType, raw string, index int) (interface{}, error) {
+	return typ.Parse(p.s, &plugin.ParseContext{
+		Context:  p.ctx,
+		Raw:      mentionRaw(typ, raw),
+		Name:     name,
+		UsedName: name,
+		Index:    index,
+		Kind:     plugin.KindArg,
+	})
+}
+
+func (p *interactionArgParser) parseFlag(f plugin.Flag, raw string) (interface{}, error) {
+	if f.GetType() == arg.Switch {
+		return raw == "true", nil
+	}
+
+	return f.GetType().Parse(p.s, &plugin.ParseContext{
+		Context:  p.ctx,
+		Raw:      mentionRaw(f.GetType(), raw),
+		Name:     "-" + f.GetName(),
+		UsedName: "-" + f.GetName(),
+		Kind:     plugin.KindFlag,
+	})
+}
+
+// mentionRaw formats the raw id of a user, role, or channel option as a
+// mention, if the passed plugin.ArgType requires mentions.
+//
+// arg.Category and arg.VoiceChannel can't be mentioned, but always accept
+// plain ids, which is why their ids are returned unchanged.
+// Custom types used for user, role, or channel options must therefore accept
+// plain ids as well.
+func mentionRaw(typ plugin.ArgType, raw string) string {
+	switch typ {
+	case arg.User, arg.Member:
+		return "<@" + raw + ">"
+	case arg.Role:
+		return "<@&" + raw + ">"
+	case arg.TextChannel:
+		return "<#" + raw + ">"
+	default:
+		return raw
+	}
+}
+
+// wrapIf wraps the passed value in a slice of its type, if wrap is true.
+// It is used for variadic arguments and multi flags, which can only be
+// specified once in an application command.
+func wrapIf(wrap bool, val interface{}) interface{} {
+	if !wrap {
+		return val
+	}
+
+	t := interfaceType
+	if val != nil {
+		t = reflect.TypeOf(val)
+	}
+
+	rval := reflect.MakeSlice(reflect.SliceOf(t), 1, 1)
+	if val != nil {
+		rval.Index(0).Set(reflect.ValueOf(val))
+	}
+
+	return rval.Interface()
+}
+
+// argDefault returns the default value for an argument or flag.
+// If slice is true, the zero value of a slice of the type's default will be
+// returned, if there is no default.
+func argDefault(def interface{}, typ plugin.ArgType, slice bool) interface{} {
+	if def != nil {
+		return def
+	}
+
+	if !slice {
+		return typ.GetDefault()
+	}
+
+	t := interfaceType
+	if typDef := typ.GetDefault(); typDef != nil {
+		t = reflect.TypeOf(typDef)
+	}
+
+	return reflect.Zero(reflect.SliceOf(t)).Interface()
+}
@@ -0,0 +1,466 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/i18n"
+	"github.com/mavolin/adam/pkg/impl/arg"
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+// argsCommand is a plugin.ResolvedCommand with the passed plugin.ArgConfig.
+type argsCommand struct {
+	plugin.ResolvedCommand
+	args plugin.ArgConfig
+}
+
+func (cmd argsCommand) Args() plugin.ArgConfig { return cmd.args }
+
+func TestInteractionInvoke(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		data   *discord.CommandInteractionData
+		expect string
+	}{
+		{
+			name:   "command",
+			data:   &discord.CommandInteractionData{Name: "abc"},
+			expect: "abc",
+		},
+		{
+			name: "command with options",
+			data: &discord.CommandInteractionData{
+				Name:    "abc",
+				Options: []discord.CommandInteractionOption{{Name: "def", Value: json.Raw(`"ghi"`)}},
+			},
+			expect: "abc",
+		},
+		{
+			name: "sub-command",
+			data: &discord.CommandInteractionData{
+				Name: "abc",
+				Options: []discord.CommandInteractionOption{
+					{
+						Name:    "def",
+						Options: []discord.CommandInteractionOption{{Name: "ghi", Value: json.Raw("123")}},
+					},
+				},
+			},
+			expect: "abc def",
+		},
+		{
+			name: "sub-command group",
+			data: &discord.CommandInteractionData{
+				Name: "abc",
+				Options: []discord.CommandInteractionOption{
+					{
+						Name:    "def",
+						Options: []discord.CommandInteractionOption{{Name: "ghi"}},
+					},
+				},
+			},
+			expect: "abc def ghi",
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := interactionInvoke(c.data)
+			assert.Equal(t, c.expect, actual)
+		})
+	}
+}
+
+func TestInteractionOptionRaw(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		value  json.Raw
+		expect string
+	}{
+		{name: "string", value: json.Raw(`"abc def"`), expect: "abc def"},
+		{name: "integer", value: json.Raw("123"), expect: "123"},
+		{name: "bool", value: json.Raw("true"), expect: "true"},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := interactionOptionRaw(discord.CommandInteractionOption{Value: c.value})
+			assert.Equal(t, c.expect, actual)
+		})
+	}
+}
+
+func TestWrapIf(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no wrap", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, 1, wrapIf(false, 1))
+	})
+
+	t.Run("wrap", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, []int{1}, wrapIf(true, 1))
+	})
+
+	t.Run("wrap nil", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, []interface{}{nil}, wrapIf(true, nil))
+	})
+}
+
+func TestArgDefault(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, 3, argDefault(3, arg.SimpleInteger, false))
+	})
+
+	t.Run("type default", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, arg.SimpleInteger.GetDefault(), argDefault(nil, arg.SimpleInteger, false))
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, []int(nil), argDefault(nil, arg.SimpleInteger, true))
+	})
+}
+
+func TestAutocompleteInvoke(t *testing.T) {
+	t.Parallel()
+
+	data := &discord.AutocompleteInteractionData{
+		Name: "abc",
+		Options: []discord.AutocompleteOption{
+			{
+				Type: discord.SubcommandGroupOption,
+				Name: "def",
+				Options: []discord.AutocompleteOption{
+					{
+						Type:    discord.SubcommandOption,
+						Name:    "ghi",
+						Options: []discord.AutocompleteOption{{Type: discord.StringOption, Name: "jkl", Focused: true}},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, "abc def ghi", autocompleteInvoke(data))
+	assert.Equal(t, []discord.AutocompleteOption{
+		{Type: discord.StringOption, Name: "jkl", Focused: true},
+	}, autocompleteArgOptions(data))
+}
+
+func TestCompleteInteractionOption(t *testing.T) {
+	t.Parallel()
+
+	cfg := &arg.Config{
+		RequiredArgs: []arg.RequiredArg{{Name: "Number", Type: arg.SimpleInteger}},
+		OptionalArgs: []arg.OptionalArg{
+			{Name: "Color", Type: arg.Choice{{Name: "red"}, {Name: "green"}, {Name: "blue"}}},
+		},
+		Flags: []arg.Flag{{Name: "shade", Type: arg.Choice{{Name: "light"}, {Name: "dark"}}}},
+	}
+
+	newCtx := func(cfg plugin.ArgConfig) *plugin.Context {
+		return &plugin.Context{
+			Localizer:      i18n.NewFallbackLocalizer(),
+			InvokedCommand: argsCommand{args: cfg},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		cfg    plugin.ArgConfig
+		opts   []discord.AutocompleteOption
+		expect []plugin.Completion
+	}{
+		{
+			name: "arg",
+			cfg:  cfg,
+			opts: []discord.AutocompleteOption{
+				{Type: discord.IntegerOption, Name: "number", Value: "12"},
+				{Type: discord.StringOption, Name: "color", Value: "gr", Focused: true},
+			},
+			expect: []plugin.Completion{{Name: "green", Value: "green"}},
+		},
+		{
+			name:   "flag",
+			cfg:    cfg,
+			opts:   []discord.AutocompleteOption{{Type: discord.StringOption, Name: "shade", Value: "d", Focused: true}},
+			expect: []plugin.Completion{{Name: "dark", Value: "dark"}},
+		},
+		{
+			name:   "not completing",
+			cfg:    cfg,
+			opts:   []discord.AutocompleteOption{{Type: discord.StringOption, Name: "number", Value: "1", Focused: true}},
+			expect: nil,
+		},
+		{
+			name:   "no focused option",
+			cfg:    cfg,
+			opts:   []discord.AutocompleteOption{{Type: discord.StringOption, Name: "color", Value: "gr"}},
+			expect: nil,
+		},
+		{
+			name:   "no args",
+			cfg:    nil,
+			opts:   []discord.AutocompleteOption{{Type: discord.StringOption, Name: "color", Value: "gr", Focused: true}},
+			expect: nil,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := completeInteractionOption(nil, newCtx(c.cfg), c.opts)
+			require.NoError(t, err)
+			assert.Equal(t, c.expect, actual)
+		})
+	}
+}
+
+func TestParseInteractionArgs(t *testing.T) {
+	t.Parallel()
+
+	option := func(name, value string) discord.CommandInteractionOption {
+		return discord.CommandInteractionOption{Name: name, Value: json.Raw(value)}
+	}
+
+	successCases := []struct {
+		name string
+		cfg  plugin.ArgConfig
+		opts []discord.CommandInteractionOption
+
+		expectArgs  plugin.Args
+		expectFlags plugin.Flags
+	}{
+		{
+			name:        "no args",
+			cfg:         &arg.Config{},
+			expectArgs:  plugin.Args{},
+			expectFlags: plugin.Flags{},
+		},
+		{
+			name: "required and optional",
+			cfg: &arg.Config{
+				RequiredArgs: []arg.RequiredArg{{Name: "Number", Type: arg.SimpleInteger}},
+				OptionalArgs: []arg.OptionalArg{
+					{Name: "Text", Type: arg.SimpleText},
+					{Name: "Other Number", Type: arg.SimpleInteger, Default: 3},
+				},
+			},
+			opts:        []discord.CommandInteractionOption{option("number", "12"), option("text", `"abc def"`)},
+			expectArgs:  plugin.Args{12, "abc def", 3},
+			expectFlags: plugin.Flags{},
+		},
+		{
+			name: "variadic required",
+			cfg: &arg.Config{
+				RequiredArgs: []arg.RequiredArg{{Name: "Numbers", Type: arg.SimpleInteger}},
+				Variadic:     true,
+			},
+			opts:        []discord.CommandInteractionOption{option("numbers", "12")},
+			expectArgs:  plugin.Args{[]int{12}},
+			expectFlags: plugin.Flags{},
+		},
+		{
+			name: "variadic optional missing",
+			cfg: &arg.Config{
+				OptionalArgs: []arg.OptionalArg{{Name: "Numbers", Type: arg.SimpleInteger}},
+				Variadic:     true,
+			},
+			expectArgs:  plugin.Args{[]int(nil)},
+			expectFlags: plugin.Flags{},
+		},
+		{
+			name: "mention",
+			cfg: &arg.Config{
+				RequiredArgs: []arg.RequiredArg{{Name: "User", Type: arg.User}},
+			},
+			opts:        []discord.CommandInteractionOption{option("user", `"123"`)},
+			expectArgs:  plugin.Args{&discord.User{ID: 123}},
+			expectFlags: plugin.Flags{},
+		},
+		{
+			name: "flags",
+			cfg: &arg.Config{
+				Flags: []arg.Flag{
+					{Name: "silent", Type: arg.Switch},
+					{Name: "force", Type: arg.Switch},
+					{Name: "count", Type: arg.SimpleInteger, Default: 3},
+					{Name: "tag", Type: arg.SimpleText, Multi: true},
+					{Name: "other-tag", Type: arg.SimpleText, Multi: true},
+				},
+			},
+			opts: []discord.CommandInteractionOption{
+				option("silent", "true"),
+				option("tag", `"abc"`),
+			},
+			expectArgs: plugin.Args{},
+			expectFlags: plugin.Flags{
+				"silent":    true,
+				"force":     false,
+				"count":     3,
+				"tag":       []string{"abc"},
+				"other-tag": []string(nil),
+			},
+		},
+		{
+			name: "sub-command",
+			cfg: &arg.Config{
+				RequiredArgs: []arg.RequiredArg{{Name: "Number", Type: arg.SimpleInteger}},
+			},
+			opts: []discord.CommandInteractionOption{
+				{
+					Name:    "def",
+					Options: []discord.CommandInteractionOption{option("number", "12")},
+				},
+			},
+			expectArgs:  plugin.Args{12},
+			expectFlags: plugin.Flags{},
+		},
+	}
+
+	newCtx := func(cfg plugin.ArgConfig, opts []discord.CommandInteractionOption) *plugin.Context {
+		return &plugin.Context{
+			Message: discord.Message{
+				Author:   discord.User{ID: 123},
+				Mentions: []discord.GuildUser{{User: discord.User{ID: 123}}},
+			},
+			Interaction: &discord.InteractionEvent{
+				Data: &discord.CommandInteractionData{Name: "abc", Options: opts},
+			},
+			Localizer:      i18n.NewFallbackLocalizer(),
+			InvokedCommand: argsCommand{args: cfg},
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		for _, c := range successCases {
+			c := c
+			t.Run(c.name, func(t *testing.T) {
+				t.Parallel()
+
+				ctx := newCtx(c.cfg, c.opts)
+
+				err := parseInteractionArgs(nil, ctx)
+				require.NoError(t, err)
+
+				assert.Equal(t, c.expectArgs, ctx.Args)
+				assert.Equal(t, c.expectFlags, ctx.Flags)
+			})
+		}
+	})
+
+	t.Run("missing required", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := newCtx(&arg.Config{
+			RequiredArgs: []arg.RequiredArg{{Name: "Number", Type: arg.SimpleInteger}},
+		}, nil)
+
+		expect := plugin.NewArgumentErrorl(missingOptionError.
+			WithPlaceholders(missingOptionErrorPlaceholders{
+				Name: "Number",
+			}))
+
+		err := parseInteractionArgs(nil, ctx)
+		assert.Equal(t, expect, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := newCtx(&arg.Config{
+			RequiredArgs: []arg.RequiredArg{{Name: "Number", Type: arg.SimpleInteger}},
+		}, []discord.CommandInteractionOption{option("number", `"abc"`)})
+
+		err := parseInteractionArgs(nil, ctx)
+		assert.IsType(t, new(plugin.ArgumentError), err)
+	})
+
+	t.Run("flag constraint", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := newCtx(&arg.Config{
+			Flags: []arg.Flag{
+				{Name: "user", Type: arg.SimpleText},
+				{Name: "role", Type: arg.SimpleText},
+			},
+			FlagConstraints: []arg.FlagConstraint{arg.MutuallyExclusive("user", "role")},
+		}, []discord.CommandInteractionOption{option("user", `"abc"`), option("role", `"def"`)})
+
+		expect := arg.MutuallyExclusive("user", "role")(map[string]string{"user": "user", "role": "role"})
+
+		err := parseInteractionArgs(nil, ctx)
+		assert.Equal(t, expect, err)
+		assert.Nil(t, ctx.Args)
+	})
+
+	t.Run("no args", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := newCtx(nil, nil)
+
+		err := parseInteractionArgs(nil, ctx)
+		require.NoError(t, err)
+		assert.Nil(t, ctx.Args)
+		assert.Nil(t, ctx.Flags)
+	})
+}
+
+func TestMentionRaw(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		typ    plugin.ArgType
+		expect string
+	}{
+		{name: "user", typ: arg.User, expect: "<@123>"},
+		{name: "member", typ: arg.Member, expect: "<@123>"},
+		{name: "role", typ: arg.Role, expect: "<@&123>"},
+		{name: "text channel", typ: arg.TextChannel, expect: "<#123>"},
+		{name: "category", typ: arg.Category, expect: "123"},
+		{name: "voice channel", typ: arg.VoiceChannel, expect: "123"},
+		{name: "other", typ: arg.SimpleText, expect: "123"},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, c.expect, mentionRaw(c.typ, "123"))
+		})
+	}
+}
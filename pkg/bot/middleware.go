@@ -36,6 +36,8 @@ type Middlewarer interface {
 // MiddlewareManagers zero value is an empty MiddlewareManager.
 type MiddlewareManager struct {
 	middlewares []Middleware
+	// names contains the names of the middlewares, as used for tracing.
+	names []string
 }
 
 var _ Middlewarer = new(MiddlewareManager)
@@ -53,6 +55,8 @@ var _ Middlewarer = new(MiddlewareManager)
 //	• func(*state.State, *state.MessageCreateEvent) error
 //	• func(*state.State, *state.MessageUpdateEvent)
 //	• func(*state.State, *state.MessageUpdateEvent) error
+//	• func(*state.State, *state.InteractionCreateEvent)
+//	• func(*state.State, *state.InteractionCreateEvent) error
 //	• func(next CommandFunc) CommandFunc
 //nolint:funlen,gocognit
 func (m *MiddlewareManager) TryAddMiddleware(f interface{}) error {
@@ -62,9 +66,12 @@ func (m *MiddlewareManager) TryAddMiddleware(f interface{}) error {
 	case func(*state.State, interface{}):
 		mf = func(next CommandFunc) CommandFunc {
 			return func(s *state.State, ctx *plugin.Context) error {
-				if !ctx.Message.EditedTimestamp.IsValid() {
+				switch {
+				case ctx.IsInteraction():
+					f(s, newInteractionCreateEvent(ctx))
+				case !ctx.Message.EditedTimestamp.IsValid():
 					f(s, newMessageCreateEvent(ctx))
-				} else {
+				default:
 					f(s, newMessageUpdateEvent(ctx))
 				}
 
@@ -73,15 +80,18 @@ func (m *MiddlewareManager) TryAddMiddleware(f interface{}) error {
 		}
 	case func(*state.State, interface{}) error:
 		mf = func(next CommandFunc) CommandFunc {
-			return func(s *state.State, ctx *plugin.Context) error {
-				if !ctx.Message.EditedTimestamp.IsValid() {
-					if err := f(s, newMessageCreateEvent(ctx)); err != nil {
-						return err
-					}
-				} else {
-					if err := f(s, newMessageUpdateEvent(ctx)); err != nil {
-						return err
-					}
+			return func(s *state.State, ctx *plugin.Context) (err error) {
+				switch {
+				case ctx.IsInteraction():
+					err = f(s, newInteractionCreateEvent(ctx))
+				case !ctx.Message.EditedTimestamp.IsValid():
+					err = f(s, newMessageCreateEvent(ctx))
+				default:
+					err = f(s, newMessageUpdateEvent(ctx))
+				}
+
+				if err != nil {
+					return err
 				}
 
 				return next(s, ctx)
@@ -108,7 +118,7 @@ func (m *MiddlewareManager) TryAddMiddleware(f interface{}) error {
 	case func(*state.State, *event.MessageCreate):
 		mf = func(next CommandFunc) CommandFunc {
 			return func(s *state.State, ctx *plugin.Context) error {
-				if !ctx.Message.EditedTimestamp.IsValid() {
+				if !ctx.IsInteraction() && !ctx.Message.EditedTimestamp.IsValid() {
 					f(s, newMessageCreateEvent(ctx))
 				}
 
@@ -118,7 +128,7 @@ func (m *MiddlewareManager) TryAddMiddleware(f interface{}) error {
 	case func(*state.State, *event.MessageCreate) error:
 		mf = func(next CommandFunc) CommandFunc {
 			return func(s *state.State, ctx *plugin.Context) error {
-				if !ctx.Message.EditedTimestamp.IsValid() {
+				if !ctx.IsInteraction() && !ctx.Message.EditedTimestamp.IsValid() {
 					if err := f(s, newMessageCreateEvent(ctx)); err != nil {
 						return err
 					}
@@ -146,6 +156,28 @@ func (m *MiddlewareManager) TryAddMiddleware(f interface{}) error {
 					}
 				}
 
+				return next(s, ctx)
+			}
+		}
+	case func(*state.State, *event.InteractionCreate):
+		mf = func(next CommandFunc) CommandFunc {
+			return func(s *state.State, ctx *plugin.Context) error {
+				if ctx.IsInteraction() {
+					f(s, newInteractionCreateEvent(ctx))
+				}
+
+				return next(s, ctx)
+			}
+		}
+	case func(*state.State, *event.InteractionCreate) error:
+		mf = func(next CommandFunc) CommandFunc {
+			return func(s *state.State, ctx *plugin.Context) error {
+				if ctx.IsInteraction() {
+					if err := f(s, newInteractionCreateEvent(ctx)); err != nil {
+						return err
+					}
+				}
+
 				return next(s, ctx)
 			}
 		}
@@ -158,6 +190,7 @@ func (m *MiddlewareManager) TryAddMiddleware(f interface{}) error {
 	}
 
 	m.middlewares = append(m.middlewares, mf)
+	m.names = append(m.names, middlewareName(f))
 	return nil
 }
 
@@ -185,6 +218,17 @@ func newMessageUpdateEvent(ctx *plugin.Context) *event.MessageUpdate {
 	}
 }
 
+// newInteractionCreateEvent creates a new state.InteractionCreateEvent from
+// the passed *plugin.Context.
+func newInteractionCreateEvent(ctx *plugin.Context) *event.InteractionCreate {
+	return &event.InteractionCreate{
+		InteractionCreateEvent: &gateway.InteractionCreateEvent{
+			InteractionEvent: *ctx.Interaction,
+		},
+		Base: ctx.Base,
+	}
+}
+
 // AddMiddleware is the same as TryAddMiddleware, but panics if TryAddMiddleware
 // returns an error.
 func (m *MiddlewareManager) AddMiddleware(f interface{}) {
@@ -201,3 +245,13 @@ func (m *MiddlewareManager) Middlewares() []Middleware {
 
 	return m.middlewares
 }
+
+// middlewareNames returns the names of the middlewares of the
+// MiddlewareManager, in the same order as returned by Middlewares.
+func (m *MiddlewareManager) middlewareNames() []string {
+	if m == nil {
+		return nil
+	}
+
+	return m.names
+}
@@ -24,6 +24,8 @@ func TestMiddlewareManager_AddMiddleware(t *testing.T) {
 		func(*state.State, *event.MessageCreate) error { return nil },
 		func(*state.State, *event.MessageUpdate) {},
 		func(*state.State, *event.MessageUpdate) error { return nil },
+		func(*state.State, *event.InteractionCreate) {},
+		func(*state.State, *event.InteractionCreate) error { return nil },
 		func(next CommandFunc) CommandFunc {
 			return func(*state.State, *plugin.Context) error { return nil }
 		},
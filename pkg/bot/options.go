@@ -1,8 +1,6 @@
 package bot
 
 import (
-	"log"
-	"runtime/debug"
 	"time"
 
 	"github.com/diamondburned/arikawa/v3/discord"
@@ -17,6 +15,7 @@ import (
 	"github.com/mavolin/adam/pkg/i18n"
 	"github.com/mavolin/adam/pkg/impl/arg"
 	"github.com/mavolin/adam/pkg/plugin"
+	"github.com/mavolin/adam/pkg/utils/logutil"
 )
 
 // Options contains different configurations for a Bot.
@@ -37,6 +36,13 @@ type Options struct {
 	//
 	// Default: nil
 	Owners []discord.UserID
+	// RestrictionProvider is the plugin.RestrictionProvider used to retrieve
+	// the restrictions configured at runtime, e.g. per-guild allow and deny
+	// lists.
+	// It is accessible through plugin.Context.RestrictionProvider.
+	//
+	// Default: nil
+	RestrictionProvider plugin.RestrictionProvider
 	// EditAge is the oldest age an edit message may have, to trigger a
 	// command.
 	// If a message older than EditAge is edited, it will be ignored.
@@ -44,6 +50,21 @@ type Options struct {
 	//
 	// Default: 0
 	EditAge time.Duration
+	// CloseGracePeriod is the maximum amount of time Bot.Close waits for the
+	// invokes in progress to return, after canceling their context.Context.
+	//
+	// Default: 5 * time.Second
+	CloseGracePeriod time.Duration
+	// JanitorInterval is the interval in which throttler.DefaultJanitor
+	// evicts expired throttling entries, while the bot is open.
+	// All throttlers and stores of package throttler register with
+	// throttler.DefaultJanitor.
+	// As it counts its starts and stops, it may be shared by multiple bots.
+	//
+	// If this is set to a negative value, the janitor won't be started.
+	//
+	// Default: 10 * time.Minute
+	JanitorInterval time.Duration
 
 	// Status is the status of the bot.
 	//
@@ -90,29 +111,71 @@ type Options struct {
 	// Default: DefaultThrottlerCancelCheck
 	ThrottlerCancelChecker func(error) bool
 
+	// RateLimit is the bot-wide rate limit applied to all commands, in
+	// addition to the throttlers of the commands.
+	// If it is nil, there is no bot-wide rate limit.
+	//
+	// Settings this field has no effect if NoDefaultMiddlewares is set to
+	// true.
+	// Use NewRateLimiter to add the rate limit manually in that case.
+	//
+	// Default: nil
+	RateLimit *RateLimit
+
+	// CommandSuggestions, if not nil, enables suggestions for unknown
+	// commands.
+	// If a user invokes a command that does not exist, the commands and
+	// modules with similar names are suggested to them.
+	//
+	// Settings this field has no effect if NoDefaultMiddlewares is set to
+	// true.
+	// Use NewSuggestingCommandFinder instead of FindCommand in that case.
+	//
+	// Default: nil
+	CommandSuggestions *CommandSuggestions
+
 	// Cabinet is the store.Cabinet used for caching.
 	// Use store.NoopCabinet to deactivate caching.
 	//
 	// Default: defaultstore.New()
 	Cabinet *store.Cabinet
 
+	// Logger is the logutil.Logger used to log errors that cannot be
+	// reported to the user.
+	// Unless replaced, GatewayErrorHandler, StateErrorHandler,
+	// StatePanicHandler, and errors.Log all log through it.
+	//
+	// Default: logutil.Std
+	Logger logutil.Logger
+
+	// TraceExporter is the TraceExporter used to export the plugin.Traces of
+	// invokes.
+	// If it is nil, tracing is disabled.
+	//
+	// Default: nil
+	TraceExporter TraceExporter
+
 	// GatewayErrorHandler is the error handler of the gateway.
 	//
-	// Default: DefaultGatewayErrorHandler
+	// Default: NewGatewayErrorHandler(Logger)
 	GatewayErrorHandler func(error)
 
 	// StateErrorHandler is the error handler of the *state.State, called if an
 	// event handler returns with an error.
 	//
-	// Default: func(err error) { log.Println("event handler:", err.String()) }
+	// Default:
+	// 	func(err error) {
+	// 		Logger.Error("event handler error", logutil.ErrorFields(err)...)
+	// 	}
 	StateErrorHandler func(error)
 	// StatePanicHandler is the panic handler of the *state.State, called if an
 	// event handler panics.
 	//
 	// Default:
 	// 	func(rec interface{}) {
-	// 		log.Printf("event handler: panic: %+v\n%s\n", rec)
-	//	}
+	// 		Logger.Error("event handler panic",
+	// 			logutil.F("panic", rec), logutil.F("stack", errors.GenerateStackTrace(1)))
+	// 	}
 	StatePanicHandler func(recovered interface{})
 
 	// ErrorHandler is the handler called if a command returns with a non-nil
@@ -142,6 +205,13 @@ type Options struct {
 	// The signature of the middleware funcs must satisfy the requirements
 	// of state middlewares.
 	MessageUpdateMiddlewares []interface{}
+	// InteractionCreateMiddlewares are the middlewares invoked before routing
+	// the command, if the command was received through an interaction create
+	// event.
+	//
+	// The signature of the middleware funcs must satisfy the requirements
+	// of state middlewares.
+	InteractionCreateMiddlewares []interface{}
 
 	// TotalShards is the total number of shards.
 	// If it is <= 0, the recommended number of shards will be used.
@@ -234,7 +304,10 @@ type Options struct {
 	//  Bot.AddMiddleware(CheckHuman) // if Options.AllowBot is true
 	//	Bot.AddMiddleware(NewSettingsRetriever(Options.SettingsProvider))
 	//  Bot.AddMiddleware(CheckPrefix)
-	//	Bot.AddMiddleware(FindCommand)
+	//	Bot.AddMiddleware(FindCommand) // if Options.CommandSuggestions is nil
+	//	Bot.AddMiddleware(NewSuggestingCommandFinder(*Options.CommandSuggestions)) // otherwise
+	//	Bot.AddMiddleware(NewRateLimiter(*Options.RateLimit)) // if Options.RateLimit is not nil
+	//	Bot.AddMiddleware(ApplyTimeout)
 	//	Bot.AddMiddleware(CheckChannelTypes)
 	//	Bot.AddMiddleware(CheckBotPermissions)
 	//	Bot.AddMiddleware(NewThrottlerChecker(Options.ThrottlerCancelChecker))
@@ -251,6 +324,14 @@ func (o *Options) SetDefaults() (err error) {
 		o.SettingsProvider = StaticSettings()
 	}
 
+	if o.CloseGracePeriod <= 0 {
+		o.CloseGracePeriod = 5 * time.Second
+	}
+
+	if o.JanitorInterval == 0 {
+		o.JanitorInterval = 10 * time.Minute
+	}
+
 	if o.ArgParser == nil {
 		o.ArgParser = &arg.DelimiterParser{Delimiter: ','}
 	}
@@ -259,17 +340,23 @@ func (o *Options) SetDefaults() (err error) {
 		o.ThrottlerCancelChecker = DefaultThrottlerCancelCheck
 	}
 
+	if o.Logger == nil {
+		o.Logger = logutil.Std
+	}
+
+	l := o.Logger
+
 	if o.GatewayErrorHandler == nil {
-		o.GatewayErrorHandler = DefaultGatewayErrorHandler
+		o.GatewayErrorHandler = NewGatewayErrorHandler(l)
 	}
 
 	if o.StateErrorHandler == nil {
-		o.StateErrorHandler = func(err error) { log.Println("event handler:", err.Error()) }
+		o.StateErrorHandler = func(err error) { l.Error("event handler error", logutil.ErrorFields(err)...) }
 	}
 
 	if o.StatePanicHandler == nil {
 		o.StatePanicHandler = func(rec interface{}) {
-			log.Printf("event handler: panic: %+v\n%s\n", rec, debug.Stack())
+			l.Error("event handler panic", logutil.F("panic", rec), logutil.F("stack", errors.GenerateStackTrace(1)))
 		}
 	}
 
@@ -367,9 +454,19 @@ func DefaultThrottlerCancelCheck(err error) bool {
 	return !errors.As(err, &ierr)
 }
 
+// DefaultGatewayErrorHandler logs all errors not filtered by
+// FilterGatewayError using logutil.Std.
 func DefaultGatewayErrorHandler(err error) {
-	if !FilterGatewayError(err) {
-		log.Println(err)
+	NewGatewayErrorHandler(logutil.Std)(err)
+}
+
+// NewGatewayErrorHandler creates a new gateway error handler, that logs all
+// errors not filtered by FilterGatewayError using the passed logutil.Logger.
+func NewGatewayErrorHandler(l logutil.Logger) func(error) {
+	return func(err error) {
+		if !FilterGatewayError(err) {
+			l.Error("gateway error", logutil.ErrorFields(err)...)
+		}
 	}
 }
 
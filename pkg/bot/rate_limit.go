@@ -0,0 +1,249 @@
+package bot
+
+import (
+	"sync"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/mavolin/disstate/v4/pkg/state"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/i18n"
+	"github.com/mavolin/adam/pkg/impl/throttler"
+	"github.com/mavolin/adam/pkg/plugin"
+	"github.com/mavolin/adam/pkg/utils/duration"
+)
+
+// RateLimit is a bot-wide rate limit, that caps the total number of command
+// invokes per user and per guild across all commands.
+// Bot owners are exempt from it.
+type RateLimit struct {
+	// UserMaxInvokes is the maximum number of invokes a user may make in
+	// UserDuration.
+	// If UserMaxInvokes or UserDuration is 0, users are not rate limited.
+	UserMaxInvokes uint
+	// UserDuration is the duration in which a user may make at most
+	// UserMaxInvokes invokes.
+	UserDuration time.Duration
+
+	// GuildMaxInvokes is the maximum number of invokes that may be made in a
+	// single guild in GuildDuration.
+	// If GuildMaxInvokes or GuildDuration is 0, guilds are not rate limited.
+	GuildMaxInvokes uint
+	// GuildDuration is the duration in which at most GuildMaxInvokes invokes
+	// may be made in a single guild.
+	GuildDuration time.Duration
+
+	// Store is the throttler.Store used to store the invokes.
+	//
+	// Default: throttler.NewMemoryStore()
+	Store throttler.Store
+
+	// CancelChecker is called every time a middleware invoked after the rate
+	// limiter, or the command itself, returns with a non-nil error.
+	// If it returns true, the invoke is released and won't count towards
+	// the rate limit.
+	// This way, invokes rejected by later middlewares, e.g. because the
+	// command is restricted, don't count.
+	//
+	// Default: DefaultThrottlerCancelCheck
+	CancelChecker func(error) bool
+
+	// RepeatOffenseThreshold is the number of times a user must exceed their
+	// user rate limit within RepeatOffenseWindow, before OnRepeatOffense is
+	// called.
+	// After OnRepeatOffense was called, the user's offenses are reset.
+	//
+	// If RepeatOffenseThreshold is 0 or OnRepeatOffense is nil, repeat
+	// offenses are not tracked.
+	RepeatOffenseThreshold uint
+	// RepeatOffenseWindow is the window in which offenses are counted.
+	// Offenses older than RepeatOffenseWindow are forgotten.
+	//
+	// Default: 10 * time.Minute
+	RepeatOffenseWindow time.Duration
+	// OnRepeatOffense is called, if a user exceeded the rate limit
+	// RepeatOffenseThreshold times within RepeatOffenseWindow, e.g. to notify
+	// moderators.
+	//
+	// It is called before the *plugin.ThrottlingError of the invoke is
+	// returned, and should therefore not block.
+	OnRepeatOffense func(s *state.State, ctx *plugin.Context, offenses uint)
+}
+
+type rateLimiter struct {
+	RateLimit
+
+	offenseMutex sync.Mutex
+	offenses     map[discord.UserID][]time.Time
+	// lastSweep is the time the offenses were last swept for users whose
+	// offenses all expired.
+	lastSweep time.Time
+}
+
+// NewRateLimiter creates a new Middleware that enforces the passed
+// RateLimit.
+// If the rate limit is exceeded, the middleware returns a localized
+// *plugin.ThrottlingError.
+//
+// NewRateLimiter must be added after FindCommand, so that only invokes of
+// commands are counted.
+func NewRateLimiter(rl RateLimit) Middleware {
+	if rl.Store == nil {
+		rl.Store = throttler.NewMemoryStore()
+	}
+
+	if rl.CancelChecker == nil {
+		rl.CancelChecker = DefaultThrottlerCancelCheck
+	}
+
+	if rl.RepeatOffenseWindow <= 0 {
+		rl.RepeatOffenseWindow = 10 * time.Minute
+	}
+
+	l := &rateLimiter{RateLimit: rl, offenses: make(map[discord.UserID][]time.Time)}
+
+	return func(next CommandFunc) CommandFunc {
+		return func(s *state.State, ctx *plugin.Context) error {
+			if ctx.IsBotOwner() {
+				return next(s, ctx)
+			}
+
+			release, err := l.check(s, ctx)
+			if err != nil {
+				return err
+			}
+
+			err = next(s, ctx)
+			if err != nil && l.CancelChecker(err) {
+				release()
+			}
+
+			return err
+		}
+	}
+}
+
+// check checks if the invoke of the passed context exceeds the rate limit.
+// If not, it returns a function that releases the invoke again.
+func (l *rateLimiter) check(s *state.State, ctx *plugin.Context) (release func(), err error) {
+	now := time.Now()
+
+	var userKey, guildKey string
+
+	release = func() {
+		if userKey != "" {
+			_ = l.Store.Release(userKey, now)
+		}
+
+		if guildKey != "" {
+			_ = l.Store.Release(guildKey, now)
+		}
+	}
+
+	if l.UserMaxInvokes > 0 && l.UserDuration > 0 {
+		key := "rate_limit:user:" + ctx.Author.ID.String()
+
+		oldest, ok, err := l.Store.Take(key, now, now.Add(-l.UserDuration), l.UserMaxInvokes)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		} else if !ok {
+			l.offend(s, ctx, now)
+			return nil, newRateLimitError(userRateLimitError, l.UserDuration-now.Sub(oldest))
+		}
+
+		userKey = key
+	}
+
+	if l.GuildMaxInvokes > 0 && l.GuildDuration > 0 && ctx.GuildID.IsValid() {
+		key := "rate_limit:guild:" + ctx.GuildID.String()
+
+		oldest, ok, err := l.Store.Take(key, now, now.Add(-l.GuildDuration), l.GuildMaxInvokes)
+		if err != nil {
+			release()
+			return nil, errors.WithStack(err)
+		} else if !ok {
+			// the invoke didn't happen, so don't count it towards the
+			// user's limit
+			release()
+			return nil, newRateLimitError(guildRateLimitError, l.GuildDuration-now.Sub(oldest))
+		}
+
+		guildKey = key
+	}
+
+	return release, nil
+}
+
+// offend records an offense of the invoking user, and calls OnRepeatOffense,
+// if the user reached the RepeatOffenseThreshold.
+func (l *rateLimiter) offend(s *state.State, ctx *plugin.Context, at time.Time) {
+	if l.RepeatOffenseThreshold == 0 || l.OnRepeatOffense == nil {
+		return
+	}
+
+	l.offenseMutex.Lock()
+
+	expired := at.Add(-l.RepeatOffenseWindow)
+
+	// Remove users whose offenses all expired, so that users that offend
+	// only once don't stay in the map forever.
+	// To keep offending cheap, this is done at most once per window.
+	if at.Sub(l.lastSweep) >= l.RepeatOffenseWindow {
+		l.sweepOffenses(expired)
+		l.lastSweep = at
+	}
+
+	offenses := expireOffenses(l.offenses[ctx.Author.ID], expired)
+	offenses = append(offenses, at)
+
+	n := uint(len(offenses))
+	if n >= l.RepeatOffenseThreshold {
+		delete(l.offenses, ctx.Author.ID)
+	} else {
+		l.offenses[ctx.Author.ID] = offenses
+	}
+
+	l.offenseMutex.Unlock()
+
+	if n >= l.RepeatOffenseThreshold {
+		l.OnRepeatOffense(s, ctx, n)
+	}
+}
+
+// sweepOffenses removes all offenses that happened before expired, and the
+// users that have no offenses left.
+//
+// The offenseMutex must be locked.
+func (l *rateLimiter) sweepOffenses(expired time.Time) {
+	for userID, offenses := range l.offenses {
+		if offenses = expireOffenses(offenses, expired); len(offenses) == 0 {
+			delete(l.offenses, userID)
+		} else {
+			l.offenses[userID] = offenses
+		}
+	}
+}
+
+// expireOffenses removes all offenses that happened before expired from the
+// passed sorted offenses.
+func expireOffenses(offenses []time.Time, expired time.Time) []time.Time {
+	for len(offenses) > 0 && offenses[0].Before(expired) {
+		offenses = offenses[1:]
+	}
+
+	return offenses
+}
+
+// newRateLimitError creates a new *plugin.ThrottlingError from the passed
+// *i18n.Config, stating the passed duration rounded up to full seconds.
+func newRateLimitError(cfg *i18n.Config, d time.Duration) *plugin.ThrottlingError {
+	d = (d + time.Second - 1).Truncate(time.Second)
+	if d < time.Second {
+		d = time.Second
+	}
+
+	return plugin.NewThrottlingErrorl(cfg.WithPlaceholders(&rateLimitErrorPlaceholders{
+		Duration: duration.Format(d),
+	}))
+}
@@ -0,0 +1,222 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/mavolin/disstate/v4/pkg/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/impl/throttler"
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	next := func(*state.State, *plugin.Context) error { return nil }
+
+	t.Run("user", func(t *testing.T) {
+		t.Parallel()
+
+		f := NewRateLimiter(RateLimit{UserMaxInvokes: 1, UserDuration: time.Minute})(next)
+
+		ctx := &plugin.Context{Message: discord.Message{Author: discord.User{ID: 123}}}
+
+		require.NoError(t, f(nil, ctx))
+
+		err := f(nil, ctx)
+		assert.IsType(t, new(plugin.ThrottlingError), err)
+	})
+
+	t.Run("bot owner", func(t *testing.T) {
+		t.Parallel()
+
+		f := NewRateLimiter(RateLimit{UserMaxInvokes: 1, UserDuration: time.Minute})(next)
+
+		ctx := &plugin.Context{
+			Message:     discord.Message{Author: discord.User{ID: 123}},
+			BotOwnerIDs: []discord.UserID{123},
+		}
+
+		require.NoError(t, f(nil, ctx))
+		assert.NoError(t, f(nil, ctx))
+	})
+
+	t.Run("guild", func(t *testing.T) {
+		t.Parallel()
+
+		store := throttler.NewMemoryStore()
+
+		f := NewRateLimiter(RateLimit{
+			UserMaxInvokes:  2,
+			UserDuration:    time.Minute,
+			GuildMaxInvokes: 1,
+			GuildDuration:   time.Minute,
+			Store:           store,
+		})(next)
+
+		ctx1 := &plugin.Context{Message: discord.Message{GuildID: 456, Author: discord.User{ID: 123}}}
+		ctx2 := &plugin.Context{Message: discord.Message{GuildID: 789, Author: discord.User{ID: 123}}}
+
+		require.NoError(t, f(nil, ctx1))
+
+		err := f(nil, ctx1)
+		require.IsType(t, new(plugin.ThrottlingError), err)
+
+		// the blocked invoke must not count towards the user's limit
+		assert.NoError(t, f(nil, ctx2))
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		t.Parallel()
+
+		rejectErr := plugin.NewRestrictionError("abc")
+
+		reject := true
+
+		f := NewRateLimiter(RateLimit{
+			UserMaxInvokes:  1,
+			UserDuration:    time.Minute,
+			GuildMaxInvokes: 1,
+			GuildDuration:   time.Minute,
+		})(func(*state.State, *plugin.Context) error {
+			if reject {
+				return rejectErr
+			}
+
+			return nil
+		})
+
+		ctx := &plugin.Context{Message: discord.Message{GuildID: 456, Author: discord.User{ID: 123}}}
+
+		// the rejected invoke must neither count towards the user's, nor
+		// towards the guild's limit
+		require.Equal(t, rejectErr, f(nil, ctx))
+
+		reject = false
+		require.NoError(t, f(nil, ctx))
+
+		err := f(nil, ctx)
+		assert.IsType(t, new(plugin.ThrottlingError), err)
+	})
+
+	t.Run("informational error", func(t *testing.T) {
+		t.Parallel()
+
+		infoErr := errors.NewInformationalError("abc")
+
+		f := NewRateLimiter(RateLimit{UserMaxInvokes: 1, UserDuration: time.Minute})(
+			func(*state.State, *plugin.Context) error { return infoErr })
+
+		ctx := &plugin.Context{Message: discord.Message{Author: discord.User{ID: 123}}}
+
+		require.Equal(t, infoErr, f(nil, ctx))
+
+		err := f(nil, ctx)
+		assert.IsType(t, new(plugin.ThrottlingError), err)
+	})
+
+	t.Run("repeat offense", func(t *testing.T) {
+		t.Parallel()
+
+		var offenses []uint
+
+		f := NewRateLimiter(RateLimit{
+			UserMaxInvokes:         1,
+			UserDuration:           time.Minute,
+			RepeatOffenseThreshold: 2,
+			RepeatOffenseWindow:    time.Minute,
+			OnRepeatOffense: func(_ *state.State, _ *plugin.Context, n uint) {
+				offenses = append(offenses, n)
+			},
+		})(next)
+
+		ctx := &plugin.Context{Message: discord.Message{Author: discord.User{ID: 123}}}
+
+		require.NoError(t, f(nil, ctx))
+
+		for i := 0; i < 4; i++ {
+			require.Error(t, f(nil, ctx))
+		}
+
+		assert.Equal(t, []uint{2, 2}, offenses)
+	})
+
+	t.Run("default repeat offense window", func(t *testing.T) {
+		t.Parallel()
+
+		var called bool
+
+		f := NewRateLimiter(RateLimit{
+			UserMaxInvokes:         1,
+			UserDuration:           time.Minute,
+			RepeatOffenseThreshold: 2,
+			OnRepeatOffense: func(*state.State, *plugin.Context, uint) {
+				called = true
+			},
+		})(next)
+
+		ctx := &plugin.Context{Message: discord.Message{Author: discord.User{ID: 123}}}
+
+		require.NoError(t, f(nil, ctx))
+		require.Error(t, f(nil, ctx))
+		require.Error(t, f(nil, ctx))
+
+		assert.True(t, called)
+	})
+}
+
+func TestRateLimiter_offend(t *testing.T) {
+	t.Parallel()
+
+	l := &rateLimiter{
+		RateLimit: RateLimit{
+			RepeatOffenseThreshold: 2,
+			RepeatOffenseWindow:    time.Minute,
+			OnRepeatOffense:        func(*state.State, *plugin.Context, uint) {},
+		},
+		offenses: make(map[discord.UserID][]time.Time),
+	}
+
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	ctx1 := &plugin.Context{Message: discord.Message{Author: discord.User{ID: 123}}}
+	ctx2 := &plugin.Context{Message: discord.Message{Author: discord.User{ID: 456}}}
+
+	l.offend(nil, ctx1, base)
+	assert.Equal(t, map[discord.UserID][]time.Time{123: {base}}, l.offenses)
+
+	l.offend(nil, ctx2, base.Add(time.Minute+time.Second))
+	assert.Equal(t, map[discord.UserID][]time.Time{456: {base.Add(time.Minute + time.Second)}}, l.offenses)
+}
+
+func TestNewRateLimitError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		d      time.Duration
+		expect string
+	}{
+		{name: "less than second", d: 100 * time.Millisecond, expect: "1s"},
+		{name: "round up", d: 1500 * time.Millisecond, expect: "2s"},
+		{name: "exact", d: 3 * time.Second, expect: "3s"},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			expect := plugin.NewThrottlingErrorl(userRateLimitError.
+				WithPlaceholders(&rateLimitErrorPlaceholders{Duration: c.expect}))
+
+			actual := newRateLimitError(userRateLimitError, c.d)
+			assert.Equal(t, expect, actual)
+		})
+	}
+}
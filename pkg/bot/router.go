@@ -1,6 +1,8 @@
 package bot
 
 import (
+	"context"
+
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/mavolin/disstate/v4/pkg/event"
 	"github.com/mavolin/disstate/v4/pkg/state"
@@ -19,10 +21,9 @@ var ErrUnknownCommand = errors.NewUserErrorl(unknownCommandErrorDescription)
 // It aborts if the message is not a valid invoke.
 //
 // When calling the bot's middlewares, it guarantees that Message, Member,
-// Base, BotOwnerIDs, Replier, Provider, DiscordDataProvider, and ErrorHandler
-// are set.
+// Base, Ctx, BotOwnerIDs, Logger, RestrictionProvider, Replier, Provider,
+// DiscordDataProvider, and ErrorHandler are set.
 // Further, Localizer will be set to a fallback localizer.
-//nolint:funlen
 func (b *Bot) Route(base *event.Base, msg *discord.Message, member *discord.Member) {
 	// discard the message if THIS bot wrote it, even if b.AllowBot
 	if msg.Author.ID == b.selfID {
@@ -33,14 +34,22 @@ func (b *Bot) Route(base *event.Base, msg *discord.Message, member *discord.Memb
 		member.User = msg.Author
 	}
 
+	b.route(b.newContext(base, msg, member))
+}
+
+// newContext creates a new *plugin.Context with all fields that Route
+// guarantees to be set.
+func (b *Bot) newContext(base *event.Base, msg *discord.Message, member *discord.Member) *plugin.Context {
 	ctx := &plugin.Context{
-		Message:     *msg,
-		Member:      member,
-		Base:        base,
-		Localizer:   i18n.NewFallbackLocalizer(),
-		BotOwnerIDs: b.Owners,
-		Replier:     replier.WrapState(b.State, false),
-		Provider:    b.pluginResolver.NewProvider(base, msg),
+		Message:             *msg,
+		Member:              member,
+		Base:                base,
+		Localizer:           i18n.NewFallbackLocalizer(),
+		BotOwnerIDs:         b.Owners,
+		Logger:              b.Logger,
+		RestrictionProvider: b.RestrictionProvider,
+		Replier:             replier.WrapState(b.State, false),
+		Provider:            b.pluginResolver.NewProvider(base, msg),
 		DiscordDataProvider: &discordDataProvider{
 			s:         b.State,
 			guildID:   msg.GuildID,
@@ -50,6 +59,31 @@ func (b *Bot) Route(base *event.Base, msg *discord.Message, member *discord.Memb
 	}
 	ctx.ErrorHandler = newCtxErrorHandler(b.State, ctx, b.ErrorHandler)
 
+	return ctx
+}
+
+// route invokes the middlewares of the bot using the passed context.
+func (b *Bot) route(ctx *plugin.Context) {
+	if !b.startInvoke(ctx) {
+		return
+	}
+
+	defer b.endInvoke(ctx)
+
+	var cancel context.CancelFunc
+
+	ctx.Ctx, cancel = context.WithCancel(b.ctx)
+	defer cancel()
+
+	if b.TraceExporter != nil {
+		ctx.Trace = plugin.NewTrace()
+		defer func() {
+			if ctx.InvokedCommand != nil {
+				b.TraceExporter.ExportTrace(ctx, ctx.Trace)
+			}
+		}()
+	}
+
 	defer func() {
 		if rec := recover(); rec != nil {
 			b.PanicHandler(rec, b.State, ctx)
@@ -63,23 +97,27 @@ func (b *Bot) Route(base *event.Base, msg *discord.Message, member *discord.Memb
 }
 
 func (b *Bot) applyMiddlewares() CommandFunc {
-	middlewares := b.Middlewares()
+	trace := b.TraceExporter != nil
+
+	middlewares := middlewaresOf(&b.MiddlewareManager, trace)
 
 	middlewares = append(middlewares, func(next CommandFunc) CommandFunc {
 		return func(s *state.State, ctx *plugin.Context) error {
+			b.setInvokedCommand(ctx)
+
 			var middlewares []Middleware
 
 			for _, mod := range ctx.InvokedCommand.SourceParents() {
 				if m, ok := mod.(Middlewarer); ok && m != nil {
-					middlewares = append(middlewares, m.Middlewares()...)
+					middlewares = append(middlewares, middlewaresOf(m, trace)...)
 				}
 			}
 
 			if m, ok := ctx.InvokedCommand.Source().(Middlewarer); ok && m != nil {
-				middlewares = append(middlewares, m.Middlewares()...)
+				middlewares = append(middlewares, middlewaresOf(m, trace)...)
 			}
 
-			middlewares = append(middlewares, b.postMiddlewares.Middlewares()...)
+			middlewares = append(middlewares, middlewaresOf(&b.postMiddlewares, trace)...)
 
 			for i := len(middlewares) - 1; i >= 0; i-- {
 				next = middlewares[i](next)
@@ -5,3 +5,40 @@ import "github.com/mavolin/adam/pkg/i18n"
 var unknownCommandErrorDescription = i18n.NewFallbackConfig(
 	"bot.error.unknown_command.description",
 	"I don't know a command with that name.")
+
+var unknownCommandSuggestionsErrorDescription = i18n.NewFallbackConfig(
+	"bot.error.unknown_command.suggestions",
+	"I don't know a command with that name. Did you mean {{.suggestions}}?")
+
+type unknownCommandSuggestionsErrorDescriptionPlaceholders struct {
+	Suggestions string
+}
+
+var unknownCommandInvokeButtonLabel = i18n.NewFallbackConfig(
+	"bot.unknown_command.invoke_button",
+	"Run {{.invoke}}")
+
+type unknownCommandInvokeButtonLabelPlaceholders struct {
+	Invoke string
+}
+
+var missingOptionError = i18n.NewFallbackConfig(
+	"bot.error.missing_option",
+	"You need to specify the `{{.name}}` option.")
+
+type missingOptionErrorPlaceholders struct {
+	Name string
+}
+
+var (
+	userRateLimitError = i18n.NewFallbackConfig(
+		"bot.error.rate_limit.user",
+		"You are using commands too quickly. You can use commands again in {{.duration}}.")
+	guildRateLimitError = i18n.NewFallbackConfig(
+		"bot.error.rate_limit.guild",
+		"Commands are used too quickly on this server. Commands can be used again in {{.duration}}.")
+)
+
+type rateLimitErrorPlaceholders struct {
+	Duration string
+}
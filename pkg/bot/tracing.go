@@ -0,0 +1,200 @@
+package bot
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mavolin/disstate/v4/pkg/state"
+
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+// TraceExporter is the interface used to export the *plugin.Traces of
+// invokes.
+//
+// If a TraceExporter is set in the bot's Options, every middleware is
+// wrapped in a plugin.Span named after the middleware's function.
+// After an invoke finished, and the bot's ErrorHandler was called, the
+// Trace of the invoke is handed to ExportTrace.
+//
+// Only invokes of commands are exported, messages that were discarded before
+// a command was found are not.
+type TraceExporter interface {
+	// ExportTrace exports the passed *plugin.Trace recorded during the invoke
+	// with the passed *plugin.Context.
+	ExportTrace(ctx *plugin.Context, t *plugin.Trace)
+}
+
+// =============================================================================
+// MemoryTraceExporter
+// =====================================================================================
+
+type (
+	// MemoryTraceExporter is a TraceExporter that stores all exported traces
+	// in memory.
+	// It is intended for testing.
+	//
+	// MemoryTraceExporter is safe for concurrent use.
+	MemoryTraceExporter struct {
+		mut    sync.Mutex
+		traces []MemoryTrace
+	}
+
+	// MemoryTrace is a trace stored by a MemoryTraceExporter.
+	MemoryTrace struct {
+		// CommandID is the id of the invoked command.
+		CommandID plugin.ID
+		// Spans are the spans of the trace, sorted by their start time.
+		Spans []plugin.Span
+	}
+)
+
+var _ TraceExporter = new(MemoryTraceExporter)
+
+// NewMemoryTraceExporter creates a new empty *MemoryTraceExporter.
+func NewMemoryTraceExporter() *MemoryTraceExporter {
+	return new(MemoryTraceExporter)
+}
+
+func (e *MemoryTraceExporter) ExportTrace(ctx *plugin.Context, t *plugin.Trace) {
+	mt := MemoryTrace{Spans: t.Spans()}
+	if ctx.InvokedCommand != nil {
+		mt.CommandID = ctx.InvokedCommand.ID()
+	}
+
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	e.traces = append(e.traces, mt)
+}
+
+// Traces returns a copy of the traces exported so far.
+func (e *MemoryTraceExporter) Traces() []MemoryTrace {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	traces := make([]MemoryTrace, len(e.traces))
+	copy(traces, e.traces)
+
+	return traces
+}
+
+// Reset removes all stored traces.
+func (e *MemoryTraceExporter) Reset() {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	e.traces = nil
+}
+
+// =============================================================================
+// Utils
+// =====================================================================================
+
+// namedMiddlewarer is the interface implemented by Middlewarers that know
+// the names of their middlewares, such as MiddlewareManager.
+type namedMiddlewarer interface {
+	Middlewarer
+	middlewareNames() []string
+}
+
+// middlewaresOf returns the middlewares of the passed Middlewarer, wrapped in
+// spans, if trace is true.
+func middlewaresOf(m Middlewarer, trace bool) []Middleware {
+	middlewares := m.Middlewares()
+	if !trace {
+		return middlewares
+	}
+
+	var names []string
+	if nm, ok := m.(namedMiddlewarer); ok {
+		names = nm.middlewareNames()
+	}
+
+	traced := make([]Middleware, len(middlewares))
+
+	for i, mw := range middlewares {
+		var name string
+		if i < len(names) {
+			name = names[i]
+		} else {
+			name = middlewareName(mw)
+		}
+
+		traced[i] = traceMiddleware(name, mw)
+	}
+
+	return traced
+}
+
+// traceMiddleware wraps the passed middleware, so that it records a
+// plugin.Span with the passed name in the invoke's plugin.Trace.
+//
+// The returned Middleware must be applied once per invoke.
+func traceMiddleware(name string, m Middleware) Middleware {
+	return func(next CommandFunc) CommandFunc {
+		var downstream time.Duration
+
+		f := m(func(s *state.State, ctx *plugin.Context) error {
+			start := time.Now()
+			err := next(s, ctx)
+			downstream += time.Since(start)
+
+			return err
+		})
+
+		return func(s *state.State, ctx *plugin.Context) error {
+			start := time.Now()
+			err := f(s, ctx)
+
+			ctx.Trace.Record(plugin.Span{
+				Name:       name,
+				Start:      start,
+				End:        time.Now(),
+				Downstream: downstream,
+			})
+
+			return err
+		}
+	}
+}
+
+// middlewareName returns the name of the passed middleware function, e.g.
+// bot.CheckBotPermissions.
+// Closures are named after their enclosing function.
+func middlewareName(f interface{}) string {
+	v := reflect.ValueOf(f)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return "unknown"
+	}
+
+	rf := runtime.FuncForPC(v.Pointer())
+	if rf == nil {
+		return "unknown"
+	}
+
+	return shortFuncName(rf.Name())
+}
+
+// shortFuncName shortens the passed fully qualified function name, by
+// removing the package path, the suffix of method values, and the suffixes of
+// closures.
+func shortFuncName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	name = strings.TrimSuffix(name, "-fm")
+
+	for {
+		i := strings.LastIndex(name, ".func")
+		if i < 0 || strings.Trim(name[i+len(".func"):], "0123456789.") != "" {
+			return name
+		}
+
+		name = name[:i]
+	}
+}
@@ -0,0 +1,123 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mavolin/disstate/v4/pkg/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+func TestMemoryTraceExporter(t *testing.T) {
+	t.Parallel()
+
+	e := NewMemoryTraceExporter()
+
+	tr := plugin.NewTrace()
+	tr.Record(plugin.Span{Name: "abc"})
+
+	e.ExportTrace(&plugin.Context{InvokedCommand: mockResolvedCommand{id: ".abc"}}, tr)
+
+	expect := []MemoryTrace{{CommandID: ".abc", Spans: []plugin.Span{{Name: "abc"}}}}
+	assert.Equal(t, expect, e.Traces())
+
+	e.Reset()
+	assert.Empty(t, e.Traces())
+}
+
+func TestMiddlewaresOf(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no trace", func(t *testing.T) {
+		t.Parallel()
+
+		var m MiddlewareManager
+		m.AddMiddleware(CheckHuman)
+
+		actual := middlewaresOf(&m, false)
+		assert.Len(t, actual, 1)
+	})
+
+	t.Run("trace", func(t *testing.T) {
+		t.Parallel()
+
+		var m MiddlewareManager
+		m.AddMiddleware(CheckHuman)
+		m.AddMiddleware(func(*state.State, interface{}) {})
+
+		middlewares := middlewaresOf(&m, true)
+		require.Len(t, middlewares, 2)
+
+		ctx := &plugin.Context{Trace: plugin.NewTrace()}
+
+		f := func(*state.State, *plugin.Context) error { return nil }
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			f = middlewares[i](f)
+		}
+
+		require.NoError(t, f(nil, ctx))
+
+		spans := ctx.Trace.Spans()
+		require.Len(t, spans, 2)
+
+		names := []string{spans[0].Name, spans[1].Name}
+		assert.ElementsMatch(t, []string{"bot.CheckHuman", "bot.TestMiddlewaresOf"}, names)
+	})
+}
+
+func TestTraceMiddleware(t *testing.T) {
+	t.Parallel()
+
+	ctx := &plugin.Context{Trace: plugin.NewTrace()}
+
+	m := traceMiddleware("outer", func(next CommandFunc) CommandFunc {
+		return func(s *state.State, ctx *plugin.Context) error {
+			return next(s, ctx)
+		}
+	})
+
+	f := m(func(*state.State, *plugin.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+
+	require.NoError(t, f(nil, ctx))
+
+	spans := ctx.Trace.Spans()
+	require.Len(t, spans, 1)
+
+	assert.Equal(t, "outer", spans[0].Name)
+	assert.GreaterOrEqual(t, int64(spans[0].Downstream), int64(10*time.Millisecond))
+	assert.LessOrEqual(t, int64(spans[0].SelfDuration()), int64(spans[0].Duration()))
+}
+
+func TestShortFuncName(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		expect string
+	}{
+		{name: "github.com/mavolin/adam/pkg/bot.CheckHuman", expect: "bot.CheckHuman"},
+		{name: "github.com/mavolin/adam/pkg/bot.NewThrottlerChecker.func1", expect: "bot.NewThrottlerChecker"},
+		{name: "github.com/mavolin/adam/pkg/bot.NewThrottlerChecker.func1.2", expect: "bot.NewThrottlerChecker"},
+		{
+			name:   "github.com/mavolin/adam/pkg/utils/metrics.(*Collector).Middleware-fm",
+			expect: "metrics.(*Collector).Middleware",
+		},
+		{name: "main.functional", expect: "main.functional"},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := shortFuncName(c.name)
+			assert.Equal(t, c.expect, actual)
+		})
+	}
+}
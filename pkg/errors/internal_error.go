@@ -3,7 +3,6 @@ package errors
 import (
 	"fmt"
 	"io"
-	"log"
 
 	"github.com/diamondburned/arikawa/v3/utils/httputil"
 	"github.com/mavolin/disstate/v4/pkg/state"
@@ -11,6 +10,7 @@ import (
 	"github.com/mavolin/adam/pkg/i18n"
 	"github.com/mavolin/adam/pkg/plugin"
 	"github.com/mavolin/adam/pkg/utils/discorderr"
+	"github.com/mavolin/adam/pkg/utils/logutil"
 )
 
 // InternalError represents a non-user triggered error.
@@ -550,16 +550,54 @@ var HandleDiscordError = func(ierr *InternalError, derr *httputil.HTTPError) Err
 
 // Log logs an InternalError.
 //
+// By default, it logs the error using the Context's Logger, or logutil.Std,
+// if the Context has none.
+// Besides the error and its stack trace, the entry contains the ids of the
+// invoked command, the guild, the channel, the user, and the message, if
+// available.
+//
 // As InternalErrors can arise during any middleware, not all fields of the
 // Context might be set.
 // Hence, nil-checks should be performed on every nillable field not set by the
 // router directly.
 var Log = func(ctx *plugin.Context, err *InternalError) {
-	if ctx.InvokedCommand == nil {
-		log.Printf("internal error: %+v\n%+v\n", err, err.StackTrace())
-	} else {
-		log.Printf("internal error in command %s: %+v\n%+v", ctx.InvokedCommand.ID(), err, err.StackTrace())
+	l := ctx.Logger
+	if l == nil {
+		l = logutil.Std
+	}
+
+	fields := []logutil.Field{logutil.F("error", err), logutil.F("stack", err.StackTrace())}
+	fields = append(fields, contextFields(ctx)...)
+
+	l.Error("internal error", fields...)
+}
+
+// contextFields returns the logutil.Fields describing the invoke of the
+// passed *plugin.Context.
+func contextFields(ctx *plugin.Context) []logutil.Field {
+	fields := make([]logutil.Field, 0, 5)
+
+	if ctx.InvokedCommand != nil {
+		fields = append(fields, logutil.F("command_id", ctx.InvokedCommand.ID()))
+	}
+
+	if ctx.GuildID.IsValid() {
+		fields = append(fields, logutil.F("guild_id", ctx.GuildID))
+	}
+
+	if ctx.ChannelID.IsValid() {
+		fields = append(fields, logutil.F("channel_id", ctx.ChannelID))
+	}
+
+	if ctx.Author.ID.IsValid() {
+		fields = append(fields, logutil.F("user_id", ctx.Author.ID))
+	}
+
+	if ctx.Message.ID.IsValid() {
+		fields = append(fields, logutil.F("message_id", ctx.Message.ID))
 	}
+
+	return fields
 }
 
 // HandleInternalError is called to handle a non-silent InternalError.
@@ -11,6 +11,7 @@ import (
 	mockplugin "github.com/mavolin/adam/internal/mock/plugin"
 	"github.com/mavolin/adam/pkg/i18n"
 	"github.com/mavolin/adam/pkg/plugin"
+	"github.com/mavolin/adam/pkg/utils/logutil"
 	"github.com/mavolin/adam/pkg/utils/mock"
 )
 
@@ -752,3 +753,58 @@ func TestInternalError_Handle(t *testing.T) {
 		require.NoError(t, err, "InternalError.Handle should never return an error")
 	})
 }
+
+type recordingLogger struct {
+	msg    string
+	fields []logutil.Field
+}
+
+func (l *recordingLogger) Error(msg string, fields ...logutil.Field) {
+	l.msg = msg
+	l.fields = fields
+}
+
+func TestLog(t *testing.T) {
+	t.Parallel()
+
+	l := new(recordingLogger)
+
+	ctx := &plugin.Context{
+		Message: discord.Message{
+			ID:        123,
+			ChannelID: 456,
+			GuildID:   789,
+			Author:    discord.User{ID: 12},
+		},
+		InvokedCommand: mock.ResolveCommand(plugin.BuiltInSource, mock.Command{Name: "abc"}),
+		Logger:         l,
+	}
+
+	err := NewWithStack("abc")
+
+	Log(ctx, err)
+
+	expect := []logutil.Field{
+		logutil.F("error", err),
+		logutil.F("stack", err.StackTrace()),
+		logutil.F("command_id", plugin.ID(".abc")),
+		logutil.F("guild_id", discord.GuildID(789)),
+		logutil.F("channel_id", discord.ChannelID(456)),
+		logutil.F("user_id", discord.UserID(12)),
+		logutil.F("message_id", discord.MessageID(123)),
+	}
+
+	assert.Equal(t, "internal error", l.msg)
+	assert.Equal(t, expect, l.fields)
+}
+
+func TestContextFields(t *testing.T) {
+	t.Parallel()
+
+	ctx := &plugin.Context{Message: discord.Message{ChannelID: 456}}
+
+	expect := []logutil.Field{logutil.F("channel_id", discord.ChannelID(456))}
+
+	actual := contextFields(ctx)
+	assert.Equal(t, expect, actual)
+}
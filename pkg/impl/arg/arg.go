@@ -20,6 +20,8 @@ type (
 
 		// Flags are the flags.
 		Flags []Flag
+		// FlagConstraints are the constraints the flags must satisfy.
+		FlagConstraints []FlagConstraint
 
 		iRequiredArgs []plugin.RequiredArg
 		iOptionalArgs []plugin.OptionalArg
@@ -107,6 +109,11 @@ func (c *Config) GetFlags() []plugin.Flag {
 	return c.iFlags
 }
 
+// GetFlagConstraints returns the FlagConstraints of the Config.
+func (c *Config) GetFlagConstraints() []FlagConstraint {
+	return c.FlagConstraints
+}
+
 func (c *Config) setInterfaces() {
 	if c.iRequiredArgs == nil && len(c.RequiredArgs) > 0 {
 		c.iRequiredArgs = make([]plugin.RequiredArg, len(c.RequiredArgs))
@@ -145,6 +152,8 @@ type (
 
 		// Flags are the flags.
 		Flags []LocalizedFlag
+		// FlagConstraints are the constraints the flags must satisfy.
+		FlagConstraints []FlagConstraint
 
 		iRequiredArgs []plugin.RequiredArg
 		iOptionalArgs []plugin.OptionalArg
@@ -266,6 +275,11 @@ func (c *LocalizedConfig) GetFlags() []plugin.Flag {
 	return c.iFlags
 }
 
+// GetFlagConstraints returns the FlagConstraints of the LocalizedConfig.
+func (c *LocalizedConfig) GetFlagConstraints() []FlagConstraint {
+	return c.FlagConstraints
+}
+
 func (c *LocalizedConfig) setInterfaces() {
 	if c.iRequiredArgs == nil && len(c.RequiredArgs) > 0 {
 		c.iRequiredArgs = make([]plugin.RequiredArg, len(c.RequiredArgs))
@@ -0,0 +1,487 @@
+package arg
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+// BindTypes are the plugin.ArgTypes that can be referenced by name, using the
+// type tag of a field bound by a Binder.
+// Custom types may be added before creating a Binder.
+var BindTypes = map[string]plugin.ArgType{
+	"text":               SimpleText,
+	"link":               SimpleLink,
+	"alphanumeric_id":    SimpleAlphanumericID,
+	"integer":            SimpleInteger,
+	"positive_integer":   PositiveInteger,
+	"negative_integer":   NegativeInteger,
+	"decimal":            SimpleDecimal,
+	"positive_decimal":   PositiveDecimal,
+	"negative_decimal":   NegativeDecimal,
+	"numeric_id":         SimpleNumericID,
+	"switch":             Switch,
+	"duration":           SimpleDuration,
+	"time":               SimpleTime,
+	"date":               SimpleDate,
+	"date_with_tz":       DateWithTZ,
+	"date_time":          SimpleDateTime,
+	"time_zone":          TimeZone,
+	"user":               User,
+	"member":             Member,
+	"role":               Role,
+	"text_channel":       TextChannel,
+	"category":           Category,
+	"voice_channel":      VoiceChannel,
+	"emoji":              Emoji,
+	"unicode_emoji":      UnicodeEmoji,
+	"raw_emoji":          RawEmoji,
+	"code":               Code,
+	"regular_expression": RegularExpression,
+	"command":            Command,
+	"module":             Module,
+	"plugin":             Plugin,
+}
+
+// inferableTypes returns the plugin.ArgTypes used for fields without a type
+// tag.
+// The first type whose default value has the same Go type as the field is
+// used.
+func inferableTypes() []plugin.ArgType {
+	return []plugin.ArgType{
+		SimpleText, SimpleInteger, SimpleDecimal, SimpleNumericID, Switch, SimpleDuration, SimpleDateTime,
+		TimeZone, User, Member, Role, TextChannel, Emoji, RawEmoji, Code, RegularExpression, Command, Module,
+	}
+}
+
+type (
+	// Binder derives a *Config from the struct tags of a struct, and binds
+	// the arguments and flags parsed using that config to the fields of such
+	// a struct.
+	//
+	// Arguments are declared using the arg tag, and flags using the flag tag.
+	// Fields without either tag are ignored, and fields with one of them must
+	// be exported.
+	//
+	// Arguments are ordered by the order of their fields.
+	// The arg tag contains the name of the argument, optionally followed by
+	// the options 'optional' and 'variadic', separated by commas.
+	// Optional arguments must follow all required arguments, and only the
+	// last argument may be variadic.
+	//
+	// The flag tag contains the name of the flag, optionally followed by the
+	// options 'aliases=<alias>|<alias>' and 'multi', separated by commas.
+	//
+	// The fields of variadic arguments and multi flags must be slices.
+	//
+	// Additionally, the following tags may be used:
+	//
+	// The type tag contains the name of the plugin.ArgType as found in
+	// BindTypes.
+	// If it is omitted, the type is inferred from the Go type of the field,
+	// e.g. SimpleText for strings, and SimpleInteger for ints.
+	//
+	// The default tag contains the default value of an optional argument or
+	// flag.
+	// It may only be used for fields of kind string, bool, int, uint, or
+	// float, or of type time.Duration.
+	//
+	// The desc tag contains the description of the argument or flag.
+	//
+	// Example
+	//
+	//	type banArgs struct {
+	//		Member *discord.Member `arg:"member" desc:"The member to ban."`
+	//		Reason string          `arg:"reason,optional" default:"No reason given."`
+	//		Days   int             `flag:"days,aliases=d" type:"positive_integer" default:"1"`
+	//		Silent bool            `flag:"silent,aliases=s"`
+	//	}
+	Binder struct {
+		typ    reflect.Type
+		config *Config
+
+		// args are the field indexes of the arguments.
+		args []int
+		// flags are the field indexes of the flags, by the flags' names.
+		flags map[string]int
+	}
+
+	// bindOptions are the options found in an arg or flag tag.
+	bindOptions struct {
+		name     string
+		optional bool
+		variadic bool
+		aliases  []string
+		multi    bool
+	}
+)
+
+// NewBinder creates a new *Binder for the struct v points to, or for v
+// itself, if v is a struct.
+//
+// It returns an error if the tags of the struct are invalid.
+func NewBinder(v interface{}) (*Binder, error) {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errors.NewWithStackf("arg: cannot bind to %T, expected a struct or a pointer to a struct", v)
+	}
+
+	b := &Binder{
+		typ:    t,
+		config: new(Config),
+		flags:  make(map[string]int),
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		argTag, isArg := f.Tag.Lookup("arg")
+		flagTag, isFlag := f.Tag.Lookup("flag")
+
+		if !isArg && !isFlag {
+			continue
+		} else if f.PkgPath != "" {
+			return nil, errors.NewWithStackf("arg: field %s: arguments and flags must be exported", f.Name)
+		}
+
+		if isArg {
+			if err := b.addArg(i, f, argTag); err != nil {
+				return nil, err
+			}
+		} else if err := b.addFlag(i, f, flagTag); err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// MustBinder is the same as NewBinder, but panics if NewBinder returns an
+// error.
+// It is intended for binders stored in package-level variables.
+func MustBinder(v interface{}) *Binder {
+	b, err := NewBinder(v)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return b
+}
+
+// Config returns the *Config derived from the struct tags.
+// It should be used as the plugin.ArgConfig of the command.
+func (b *Binder) Config() *Config {
+	return b.config
+}
+
+// Bind fills the struct dst points to with the arguments and flags found in
+// the passed *plugin.Context.
+// dst must be a pointer to a struct of the type the Binder was created for,
+// and the Context's arguments must have been parsed using the Binder's
+// Config.
+func (b *Binder) Bind(ctx *plugin.Context, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Type() != b.typ {
+		return errors.NewWithStackf("arg: cannot bind to %T, expected *%s", dst, b.typ)
+	}
+
+	v := rv.Elem()
+
+	for i, fieldIndex := range b.args {
+		if i >= len(ctx.Args) {
+			break
+		}
+
+		if err := b.set(v, fieldIndex, ctx.Args[i]); err != nil {
+			return err
+		}
+	}
+
+	for name, fieldIndex := range b.flags {
+		val, ok := ctx.Flags[name]
+		if !ok {
+			continue
+		}
+
+		if err := b.set(v, fieldIndex, val); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// set sets the field with the passed index to val.
+func (b *Binder) set(v reflect.Value, fieldIndex int, val interface{}) error {
+	field := v.Field(fieldIndex)
+
+	if val == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	rval := reflect.ValueOf(val)
+	if rval.Type().AssignableTo(field.Type()) {
+		field.Set(rval)
+		return nil
+	}
+
+	// the slices of variadic arguments and multi flags are typed after
+	// their first element, which may be a concrete type, e.g. when binding
+	// plugin.ResolvedCommands
+	if rval.Kind() == reflect.Slice && field.Kind() == reflect.Slice {
+		s := reflect.MakeSlice(field.Type(), rval.Len(), rval.Len())
+
+		for i := 0; i < rval.Len(); i++ {
+			elem := rval.Index(i)
+			if elem.Kind() == reflect.Interface {
+				elem = elem.Elem()
+			}
+
+			if !elem.IsValid() {
+				continue
+			} else if !elem.Type().AssignableTo(s.Type().Elem()) {
+				return newBindError(b.typ.Field(fieldIndex), val)
+			}
+
+			s.Index(i).Set(elem)
+		}
+
+		field.Set(s)
+		return nil
+	}
+
+	return newBindError(b.typ.Field(fieldIndex), val)
+}
+
+// newBindError returns the error used if val cannot be bound to the passed
+// field.
+func newBindError(f reflect.StructField, val interface{}) error {
+	return errors.NewWithStackf("arg: cannot bind %T to field %s of type %s", val, f.Name, f.Type)
+}
+
+func (b *Binder) addArg(fieldIndex int, f reflect.StructField, tag string) error {
+	opts, err := parseBindOptions(f, tag, false)
+	if err != nil {
+		return err
+	}
+
+	if b.config.Variadic {
+		return errors.NewWithStackf("arg: field %s: only the last argument may be variadic", f.Name)
+	}
+
+	typ, err := bindType(f, opts.variadic)
+	if err != nil {
+		return err
+	}
+
+	desc := f.Tag.Get("desc")
+
+	if !opts.optional {
+		if len(b.config.OptionalArgs) > 0 {
+			return errors.NewWithStackf("arg: field %s: required arguments must precede optional arguments",
+				f.Name)
+		} else if _, ok := f.Tag.Lookup("default"); ok {
+			return errors.NewWithStackf("arg: field %s: required arguments may not have a default", f.Name)
+		}
+
+		b.config.RequiredArgs = append(b.config.RequiredArgs, RequiredArg{
+			Name:        opts.name,
+			Type:        typ,
+			Description: desc,
+		})
+	} else {
+		def, err := bindDefault(f, opts.variadic)
+		if err != nil {
+			return err
+		}
+
+		b.config.OptionalArgs = append(b.config.OptionalArgs, OptionalArg{
+			Name:        opts.name,
+			Type:        typ,
+			Default:     def,
+			Description: desc,
+		})
+	}
+
+	b.config.Variadic = opts.variadic
+	b.args = append(b.args, fieldIndex)
+
+	return nil
+}
+
+func (b *Binder) addFlag(fieldIndex int, f reflect.StructField, tag string) error {
+	opts, err := parseBindOptions(f, tag, true)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := b.flags[opts.name]; ok {
+		return errors.NewWithStackf("arg: field %s: duplicate flag %s", f.Name, opts.name)
+	}
+
+	typ, err := bindType(f, opts.multi)
+	if err != nil {
+		return err
+	}
+
+	def, err := bindDefault(f, opts.multi)
+	if err != nil {
+		return err
+	}
+
+	b.config.Flags = append(b.config.Flags, Flag{
+		Name:        opts.name,
+		Aliases:     opts.aliases,
+		Type:        typ,
+		Default:     def,
+		Description: f.Tag.Get("desc"),
+		Multi:       opts.multi,
+	})
+
+	b.flags[opts.name] = fieldIndex
+
+	return nil
+}
+
+// parseBindOptions parses the options of the passed arg or flag tag.
+func parseBindOptions(f reflect.StructField, tag string, flag bool) (*bindOptions, error) {
+	split := strings.Split(tag, ",")
+
+	opts := &bindOptions{name: strings.TrimSpace(split[0])}
+	if opts.name == "" {
+		return nil, errors.NewWithStackf("arg: field %s: missing name", f.Name)
+	}
+
+	for _, opt := range split[1:] {
+		opt = strings.TrimSpace(opt)
+
+		switch {
+		case !flag && opt == "optional":
+			opts.optional = true
+		case !flag && opt == "variadic":
+			opts.variadic = true
+		case flag && opt == "multi":
+			opts.multi = true
+		case flag && strings.HasPrefix(opt, "aliases="):
+			opts.aliases = strings.Split(strings.TrimPrefix(opt, "aliases="), "|")
+		default:
+			return nil, errors.NewWithStackf("arg: field %s: unknown option %q", f.Name, opt)
+		}
+	}
+
+	return opts, nil
+}
+
+// bindType returns the plugin.ArgType of the passed field.
+// If slice is true, the field must be a slice of the type's Go type.
+func bindType(f reflect.StructField, slice bool) (plugin.ArgType, error) {
+	t := f.Type
+	if slice {
+		if t.Kind() != reflect.Slice {
+			return nil, errors.NewWithStackf("arg: field %s: variadic arguments and multi flags must be slices",
+				f.Name)
+		}
+
+		t = t.Elem()
+	}
+
+	if name, ok := f.Tag.Lookup("type"); ok {
+		typ, ok := BindTypes[name]
+		if !ok {
+			return nil, errors.NewWithStackf("arg: field %s: unknown type %q", f.Name, name)
+		}
+
+		if !defaultAssignable(typ, t) {
+			return nil, errors.NewWithStackf("arg: field %s: type %q cannot be bound to %s",
+				f.Name, name, f.Type)
+		}
+
+		return typ, nil
+	}
+
+	for _, typ := range inferableTypes() {
+		if def := typ.GetDefault(); def != nil && reflect.TypeOf(def) == t {
+			return typ, nil
+		}
+	}
+
+	return nil, errors.NewWithStackf("arg: field %s: cannot infer type of %s, use the type tag", f.Name, f.Type)
+}
+
+// defaultAssignable checks if the values returned by the passed
+// plugin.ArgType are assignable to the passed reflect.Type.
+func defaultAssignable(typ plugin.ArgType, t reflect.Type) bool {
+	def := typ.GetDefault()
+	if def == nil {
+		return t.Kind() == reflect.Interface
+	}
+
+	return reflect.TypeOf(def).AssignableTo(t)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// bindDefault parses the default tag of the passed field.
+// If the field has no default tag, bindDefault returns nil.
+func bindDefault(f reflect.StructField, slice bool) (interface{}, error) {
+	raw, ok := f.Tag.Lookup("default")
+	if !ok {
+		return nil, nil
+	}
+
+	if slice {
+		return nil, errors.NewWithStackf("arg: field %s: variadic arguments and multi flags may not have a default",
+			f.Name)
+	}
+
+	v := reflect.New(f.Type).Elem()
+
+	var err error
+
+	switch {
+	case f.Type == durationType:
+		var d time.Duration
+		if d, err = time.ParseDuration(raw); err == nil {
+			v.SetInt(int64(d))
+		}
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(raw); err == nil {
+			v.SetBool(b)
+		}
+	case v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(raw, 10, f.Type.Bits()); err == nil {
+			v.SetInt(i)
+		}
+	case v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64:
+		var u uint64
+		if u, err = strconv.ParseUint(raw, 10, f.Type.Bits()); err == nil {
+			v.SetUint(u)
+		}
+	case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+		var fl float64
+		if fl, err = strconv.ParseFloat(raw, f.Type.Bits()); err == nil {
+			v.SetFloat(fl)
+		}
+	default:
+		return nil, errors.NewWithStackf("arg: field %s: defaults are not supported for %s", f.Name, f.Type)
+	}
+
+	if err != nil {
+		return nil, errors.NewWithStackf("arg: field %s: invalid default %q: %s", f.Name, raw, err.Error())
+	}
+
+	return v.Interface(), nil
+}
@@ -0,0 +1,237 @@
+package arg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+type testBindArgs struct {
+	Member *discord.Member `arg:"member" desc:"The member."`
+	Reason string          `arg:"reason,optional" default:"none"`
+	Days   int             `flag:"days,aliases=d" type:"positive_integer" default:"1"`
+	Wait   time.Duration   `flag:"wait" default:"1m"`
+	Tags   []string        `flag:"tag,multi"`
+	Silent bool            `flag:"silent,aliases=s|q"`
+
+	Ignored string
+}
+
+func TestNewBinder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := NewBinder(new(testBindArgs))
+		require.NoError(t, err)
+
+		expect := &Config{
+			RequiredArgs: []RequiredArg{
+				{Name: "member", Type: Member, Description: "The member."},
+			},
+			OptionalArgs: []OptionalArg{
+				{Name: "reason", Type: SimpleText, Default: "none"},
+			},
+			Flags: []Flag{
+				{Name: "days", Aliases: []string{"d"}, Type: PositiveInteger, Default: 1},
+				{Name: "wait", Type: SimpleDuration, Default: time.Minute},
+				{Name: "tag", Type: SimpleText, Multi: true},
+				{Name: "silent", Aliases: []string{"s", "q"}, Type: Switch},
+			},
+		}
+
+		assert.Equal(t, expect, b.Config())
+	})
+
+	t.Run("variadic", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := NewBinder(struct {
+			Numbers []int `arg:"numbers,variadic"`
+		}{})
+		require.NoError(t, err)
+
+		expect := &Config{
+			RequiredArgs: []RequiredArg{{Name: "numbers", Type: SimpleInteger}},
+			Variadic:     true,
+		}
+
+		assert.Equal(t, expect, b.Config())
+	})
+
+	failureCases := []struct {
+		name string
+		v    interface{}
+	}{
+		{name: "not a struct", v: new(int)},
+		{
+			name: "missing name",
+			v: struct {
+				A string `arg:",optional"`
+			}{},
+		},
+		{
+			name: "unknown option",
+			v: struct {
+				A string `arg:"a,multi"`
+			}{},
+		},
+		{
+			name: "required after optional",
+			v: struct {
+				A string `arg:"a,optional"`
+				B string `arg:"b"`
+			}{},
+		},
+		{
+			name: "required with default",
+			v: struct {
+				A string `arg:"a" default:"abc"`
+			}{},
+		},
+		{
+			name: "argument after variadic",
+			v: struct {
+				A []string `arg:"a,variadic"`
+				B string   `arg:"b,optional"`
+			}{},
+		},
+		{
+			name: "variadic not a slice",
+			v: struct {
+				A string `arg:"a,variadic"`
+			}{},
+		},
+		{
+			name: "unknown type",
+			v: struct {
+				A string `arg:"a" type:"abc"`
+			}{},
+		},
+		{
+			name: "type mismatch",
+			v: struct {
+				A string `arg:"a" type:"integer"`
+			}{},
+		},
+		{
+			name: "cannot infer",
+			v: struct {
+				A complex128 `arg:"a"`
+			}{},
+		},
+		{
+			name: "invalid default",
+			v: struct {
+				A int `flag:"a" default:"abc"`
+			}{},
+		},
+		{
+			name: "unexported argument",
+			v: struct {
+				a string `arg:"a"`
+			}{},
+		},
+		{
+			name: "unexported flag",
+			v: struct {
+				a bool `flag:"a"`
+			}{},
+		},
+		{
+			name: "duplicate flag",
+			v: struct {
+				A string `flag:"a"`
+				B string `flag:"a"`
+			}{},
+		},
+	}
+
+	for _, c := range failureCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewBinder(c.v)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestBinder_Bind(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		b := MustBinder(new(testBindArgs))
+
+		member := &discord.Member{User: discord.User{ID: 123}}
+
+		ctx := &plugin.Context{
+			Args: plugin.Args{member, "abc"},
+			Flags: plugin.Flags{
+				"days":   2,
+				"wait":   time.Minute,
+				"tag":    []string{"a", "b"},
+				"silent": true,
+			},
+		}
+
+		var actual testBindArgs
+		require.NoError(t, b.Bind(ctx, &actual))
+
+		expect := testBindArgs{
+			Member: member,
+			Reason: "abc",
+			Days:   2,
+			Wait:   time.Minute,
+			Tags:   []string{"a", "b"},
+			Silent: true,
+		}
+
+		assert.Equal(t, expect, actual)
+	})
+
+	t.Run("interface slice", func(t *testing.T) {
+		t.Parallel()
+
+		type args struct {
+			Plugins []interface{} `arg:"plugins,variadic" type:"plugin"`
+		}
+
+		b := MustBinder(new(args))
+
+		ctx := &plugin.Context{Args: plugin.Args{[]string{"a", "b"}}}
+
+		var actual args
+		require.NoError(t, b.Bind(ctx, &actual))
+		assert.Equal(t, []interface{}{"a", "b"}, actual.Plugins)
+	})
+
+	t.Run("wrong destination", func(t *testing.T) {
+		t.Parallel()
+
+		b := MustBinder(new(testBindArgs))
+
+		err := b.Bind(new(plugin.Context), new(struct{}))
+		assert.Error(t, err)
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		b := MustBinder(new(testBindArgs))
+
+		ctx := &plugin.Context{Args: plugin.Args{"abc"}}
+
+		err := b.Bind(ctx, new(testBindArgs))
+		assert.Error(t, err)
+	})
+}
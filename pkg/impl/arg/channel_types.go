@@ -58,6 +58,8 @@ var TextChannel plugin.ArgType = new(textChannel)
 
 type textChannel struct{}
 
+var _ plugin.CompletingArgType = new(textChannel)
+
 func (t textChannel) GetName(l *i18n.Localizer) string {
 	name, _ := l.Localize(textChannelName) // we have a fallback
 	return name
@@ -129,6 +131,30 @@ func (t textChannel) Parse(s *state.State, ctx *plugin.ParseContext) (interface{
 	return c, nil
 }
 
+// Complete returns the text and news channels of the invoking guild matching
+// the input.
+// In direct messages, Complete returns no Completions.
+func (t textChannel) Complete(s *state.State, ctx *plugin.ParseContext, max int) ([]plugin.Completion, error) {
+	if !ctx.GuildID.IsValid() {
+		return nil, nil
+	}
+
+	channels, err := s.Channels(ctx.GuildID)
+	if err != nil {
+		return nil, err
+	}
+
+	comp := newCompleter(strings.TrimPrefix(ctx.Raw, "#"))
+
+	for _, c := range channels {
+		if c.Type == discord.GuildText || c.Type == discord.GuildNews {
+			comp.add(plugin.Completion{Name: "#" + c.Name, Value: c.Mention()}, c.Name)
+		}
+	}
+
+	return comp.completions(max), nil
+}
+
 func (t textChannel) GetDefault() interface{} {
 	return (*discord.Channel)(nil)
 }
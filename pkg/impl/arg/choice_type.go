@@ -30,7 +30,7 @@ type (
 	}
 )
 
-var _ plugin.ArgType = Choice{}
+var _ plugin.CompletingArgType = Choice{}
 
 func (c Choice) GetName(l *i18n.Localizer) string {
 	name, _ := l.Localize(choiceName) // we have a fallback
@@ -68,6 +68,21 @@ func (c Choice) Parse(_ *state.State, ctx *plugin.ParseContext) (interface{}, er
 	return nil, newArgumentError(choiceInvalidError, ctx, nil)
 }
 
+// Complete returns the elements whose name or aliases match the input.
+func (c Choice) Complete(_ *state.State, ctx *plugin.ParseContext, max int) ([]plugin.Completion, error) {
+	comp := newCompleter(ctx.Raw)
+
+	for _, e := range c {
+		names := make([]string, len(e.Aliases)+1)
+		names[0] = e.Name
+		copy(names[1:], e.Aliases)
+
+		comp.add(plugin.Completion{Name: e.Name, Value: e.Name}, names...)
+	}
+
+	return comp.completions(max), nil
+}
+
 // GetDefault tries to derive the default type from the value of the first
 // choice.
 // If the choice is empty, Default returns nil.
@@ -97,6 +112,8 @@ type (
 	}
 )
 
+var _ plugin.CompletingArgType = LocalizedChoice{}
+
 func (c LocalizedChoice) GetName(l *i18n.Localizer) string {
 	name, _ := l.Localize(choiceName) // we have a fallback
 	return name
@@ -125,6 +142,34 @@ func (c LocalizedChoice) Parse(_ *state.State, ctx *plugin.ParseContext) (interf
 	return nil, newArgumentError(choiceInvalidError, ctx, nil)
 }
 
+// Complete returns the elements whose localized names match the input.
+// The first name of an element that can be localized is used as the name
+// and value of the Completion.
+func (c LocalizedChoice) Complete(
+	_ *state.State, ctx *plugin.ParseContext, max int,
+) ([]plugin.Completion, error) {
+	comp := newCompleter(ctx.Raw)
+
+	for _, e := range c {
+		names := make([]string, 0, len(e.Names))
+
+		for _, nameConfig := range e.Names {
+			name, err := ctx.Localizer.Localize(nameConfig)
+			if err != nil {
+				continue
+			}
+
+			names = append(names, name)
+		}
+
+		if len(names) > 0 {
+			comp.add(plugin.Completion{Name: names[0], Value: names[0]}, names...)
+		}
+	}
+
+	return comp.completions(max), nil
+}
+
 // GetDefault tries to derive the default type from the value of the first
 // choice.
 // If the choice is empty, Default returns nil.
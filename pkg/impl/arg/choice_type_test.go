@@ -72,6 +72,64 @@ func TestChoice_Parse(t *testing.T) {
 	})
 }
 
+func TestChoice_Complete(t *testing.T) {
+	t.Parallel()
+
+	choice := Choice{
+		{Name: "banana"},
+		{Name: "apple", Aliases: []string{"pineapple"}},
+		{Name: "grape"},
+		{Name: "grapefruit"},
+	}
+
+	testCases := []struct {
+		name   string
+		raw    string
+		max    int
+		expect []plugin.Completion
+	}{
+		{
+			name: "prefix before contains",
+			raw:  "AP",
+			max:  5,
+			expect: []plugin.Completion{
+				{Name: "apple", Value: "apple"},
+				{Name: "grape", Value: "grape"},
+				{Name: "grapefruit", Value: "grapefruit"},
+			},
+		},
+		{
+			name: "exact before prefix",
+			raw:  "grape",
+			max:  5,
+			expect: []plugin.Completion{
+				{Name: "grape", Value: "grape"},
+				{Name: "grapefruit", Value: "grapefruit"},
+			},
+		},
+		{
+			name:   "max",
+			raw:    "a",
+			max:    1,
+			expect: []plugin.Completion{{Name: "apple", Value: "apple"}},
+		},
+		{name: "no match", raw: "cherry", max: 5},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := &plugin.ParseContext{Raw: c.raw}
+
+			actual, err := choice.Complete(nil, ctx, c.max)
+			require.NoError(t, err)
+			assert.Equal(t, c.expect, actual)
+		})
+	}
+}
+
 func TestChoice_Default(t *testing.T) {
 	t.Parallel()
 
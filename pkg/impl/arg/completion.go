@@ -0,0 +1,133 @@
+package arg
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mavolin/disstate/v4/pkg/state"
+
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+// MaxSuggestions is a global flag that defines the maximum number of values
+// suggested to the user, if a plugin.CompletingArgType fails to parse an
+// argument or flag.
+// Setting it to 0 disables suggestions.
+var MaxSuggestions = 3
+
+const (
+	matchExact = iota
+	matchPrefix
+	matchContains
+)
+
+type (
+	// completer collects the Completions matching a partial input.
+	completer struct {
+		input   string
+		matches []completionMatch
+	}
+
+	// completionMatch is a Completion, ranked by how well it matches the
+	// input.
+	completionMatch struct {
+		plugin.Completion
+		rank int
+	}
+)
+
+func newCompleter(input string) *completer {
+	return &completer{input: strings.ToLower(strings.TrimSpace(input))}
+}
+
+// add adds the passed plugin.Completion, if any of the passed names matches
+// the input.
+// Matching is done case-insensitively.
+func (c *completer) add(comp plugin.Completion, names ...string) {
+	rank := -1
+
+	for _, name := range names {
+		name = strings.ToLower(name)
+
+		var r int
+
+		switch {
+		case name == c.input:
+			r = matchExact
+		case strings.HasPrefix(name, c.input):
+			r = matchPrefix
+		case strings.Contains(name, c.input):
+			r = matchContains
+		default:
+			continue
+		}
+
+		if rank == -1 || r < rank {
+			rank = r
+		}
+	}
+
+	if rank != -1 {
+		c.matches = append(c.matches, completionMatch{Completion: comp, rank: rank})
+	}
+}
+
+// completions returns at most max of the collected Completions, with exact
+// matches before prefix matches before all other matches.
+// Completions of the same rank are sorted by name.
+func (c *completer) completions(max int) []plugin.Completion {
+	if len(c.matches) == 0 || max <= 0 {
+		return nil
+	}
+
+	sort.SliceStable(c.matches, func(i, j int) bool {
+		if c.matches[i].rank != c.matches[j].rank {
+			return c.matches[i].rank < c.matches[j].rank
+		}
+
+		return c.matches[i].Name < c.matches[j].Name
+	})
+
+	if len(c.matches) > max {
+		c.matches = c.matches[:max]
+	}
+
+	completions := make([]plugin.Completion, len(c.matches))
+	for i, m := range c.matches {
+		completions[i] = m.Completion
+	}
+
+	return completions
+}
+
+// withSuggestions adds the Completions of typ as suggestions to err, if err
+// is a *plugin.ArgumentError and typ is a plugin.CompletingArgType.
+// Otherwise, or if completing fails, err is returned unchanged.
+func withSuggestions(s *state.State, typ plugin.ArgType, ctx *plugin.ParseContext, err error) error {
+	//goland:noinspection GoBoolExpressions
+	if MaxSuggestions <= 0 {
+		return err
+	}
+
+	aerr, ok := err.(*plugin.ArgumentError) //nolint:errorlint
+	if !ok {
+		return err
+	}
+
+	completingTyp, ok := typ.(plugin.CompletingArgType)
+	if !ok {
+		return err
+	}
+
+	completions, cerr := completingTyp.Complete(s, ctx, MaxSuggestions)
+	if cerr != nil || len(completions) == 0 {
+		return err
+	}
+
+	suggestions := make([]string, len(completions))
+	for i, c := range completions {
+		suggestions[i] = c.Name
+	}
+
+	return aerr.WithSuggestions(suggestions...)
+}
@@ -0,0 +1,60 @@
+package arg
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+func TestWithSuggestions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("suggestions", func(t *testing.T) {
+		t.Parallel()
+
+		choice := Choice{{Name: "abc"}, {Name: "abd"}}
+
+		ctx := &plugin.ParseContext{Raw: "ab"}
+
+		_, err := choice.Parse(nil, ctx)
+
+		expect := newArgumentError(choiceInvalidError, ctx, nil).WithSuggestions("abc", "abd")
+
+		actual := withSuggestions(nil, choice, ctx, err)
+		assert.Equal(t, expect, actual)
+	})
+
+	t.Run("no completions", func(t *testing.T) {
+		t.Parallel()
+
+		choice := Choice{{Name: "abc"}}
+
+		ctx := &plugin.ParseContext{Raw: "def"}
+
+		_, expect := choice.Parse(nil, ctx)
+
+		actual := withSuggestions(nil, choice, ctx, expect)
+		assert.Equal(t, expect, actual)
+	})
+
+	t.Run("not completing", func(t *testing.T) {
+		t.Parallel()
+
+		expect := plugin.NewArgumentError("abc")
+
+		actual := withSuggestions(nil, mockTypeString, new(plugin.ParseContext), expect)
+		assert.Equal(t, expect, actual)
+	})
+
+	t.Run("not an argument error", func(t *testing.T) {
+		t.Parallel()
+
+		expect := errors.New("abc")
+
+		actual := withSuggestions(nil, Choice{{Name: "abc"}}, &plugin.ParseContext{Raw: "ab"}, expect)
+		assert.Equal(t, expect, actual)
+	})
+}
@@ -34,6 +34,11 @@ type DelimiterParser struct {
 	//
 	// Default: ','
 	Delimiter rune
+	// Prompt, if not nil, is used to ask the user for missing required
+	// arguments.
+	//
+	// Default: nil
+	Prompt *Prompt
 }
 
 var _ plugin.ArgParser = new(DelimiterParser)
@@ -43,7 +48,11 @@ func (p *DelimiterParser) Parse(args string, argConfig plugin.ArgConfig, s *stat
 		p.Delimiter = ','
 	}
 
-	return newDelimiterParser(args, argConfig, p.Delimiter, s, ctx).parse()
+	dp := newDelimiterParser(args, argConfig, p.Delimiter, s, ctx)
+	dp.helper.prompt = p.Prompt
+	dp.helper.flagConstraints = flagConstraints(argConfig)
+
+	return dp.parse()
 }
 
 // FormatArgs formats the arguments.
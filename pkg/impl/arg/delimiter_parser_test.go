@@ -583,6 +583,29 @@ func TestDelimiterParser_Parse(t *testing.T) {
 					Name: "abc",
 				})),
 		},
+		{
+			name: "flag constraint",
+			config: &Config{
+				Flags: []Flag{
+					{
+						Name: "abc",
+						Type: Switch,
+					},
+					{
+						Name:    "def",
+						Aliases: []string{"ghi"},
+						Type:    Switch,
+					},
+				},
+				FlagConstraints: []FlagConstraint{MutuallyExclusive("abc", "def")},
+			},
+			rawArgs: "-abc, -ghi",
+			expect: plugin.NewArgumentErrorl(mutuallyExclusiveFlagsError.
+				WithPlaceholders(mutuallyExclusiveFlagsErrorPlaceholders{
+					First:  "abc",
+					Second: "ghi",
+				})),
+		},
 	}
 
 	t.Run("failure", func(t *testing.T) {
@@ -0,0 +1,128 @@
+package arg
+
+import (
+	"strings"
+
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+// FlagConstraint is a constraint on the flags used in an invoke.
+// Constraints are checked after all flags were parsed.
+//
+// used maps the names of all flags that were used to the name or alias the
+// user used them with.
+// If the constraint is not satisfied, a *plugin.ArgumentError describing the
+// conflict is returned.
+type FlagConstraint func(used map[string]string) *plugin.ArgumentError
+
+// flagConstrainer is the interface implemented by the plugin.ArgConfigs of
+// this package, that support FlagConstraints.
+type flagConstrainer interface {
+	GetFlagConstraints() []FlagConstraint
+}
+
+var (
+	_ flagConstrainer = new(Config)
+	_ flagConstrainer = new(LocalizedConfig)
+)
+
+// flagConstraints returns the FlagConstraints of the passed
+// plugin.ArgConfig, if it supports them.
+func flagConstraints(cfg plugin.ArgConfig) []FlagConstraint {
+	if c, ok := cfg.(flagConstrainer); ok {
+		return c.GetFlagConstraints()
+	}
+
+	return nil
+}
+
+// CheckFlagConstraints checks the FlagConstraints of the passed
+// plugin.ArgConfig, if it supports them.
+// used maps the names of all flags that were used to the name or alias the
+// user used them with.
+//
+// The parsers of this package check the constraints themselves.
+// CheckFlagConstraints is intended for parsers that don't use them, such as
+// the parser of application command options.
+func CheckFlagConstraints(cfg plugin.ArgConfig, used map[string]string) error {
+	for _, c := range flagConstraints(cfg) {
+		if err := c(used); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MutuallyExclusive creates a new FlagConstraint that allows at most one of
+// the flags with the passed names to be used.
+func MutuallyExclusive(names ...string) FlagConstraint {
+	return func(used map[string]string) *plugin.ArgumentError {
+		var first string
+
+		for _, name := range names {
+			usedName, ok := used[name]
+			if !ok {
+				continue
+			}
+
+			if first == "" {
+				first = usedName
+				continue
+			}
+
+			return plugin.NewArgumentErrorl(mutuallyExclusiveFlagsError.
+				WithPlaceholders(mutuallyExclusiveFlagsErrorPlaceholders{
+					First:  first,
+					Second: usedName,
+				}))
+		}
+
+		return nil
+	}
+}
+
+// Requires creates a new FlagConstraint that requires all flags with the
+// passed required names to be used, if the flag with the passed name is used.
+func Requires(name string, required ...string) FlagConstraint {
+	return func(used map[string]string) *plugin.ArgumentError {
+		usedName, ok := used[name]
+		if !ok {
+			return nil
+		}
+
+		for _, r := range required {
+			if _, ok := used[r]; !ok {
+				return plugin.NewArgumentErrorl(requiredFlagMissingError.
+					WithPlaceholders(requiredFlagMissingErrorPlaceholders{
+						Flag:     usedName,
+						Required: r,
+					}))
+			}
+		}
+
+		return nil
+	}
+}
+
+// AtLeastOneOf creates a new FlagConstraint that requires at least one of the
+// flags with the passed names to be used.
+func AtLeastOneOf(names ...string) FlagConstraint {
+	return func(used map[string]string) *plugin.ArgumentError {
+		for _, name := range names {
+			if _, ok := used[name]; ok {
+				return nil
+			}
+		}
+
+		flags := make([]string, len(names))
+		for i, name := range names {
+			flags[i] = "`-" + name + "`"
+		}
+
+		return plugin.NewArgumentErrorl(noneOfFlagsUsedError.
+			WithPlaceholders(noneOfFlagsUsedErrorPlaceholders{
+				Flags: strings.Join(flags, ", "),
+			}))
+	}
+}
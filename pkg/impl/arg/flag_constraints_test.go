@@ -0,0 +1,125 @@
+package arg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/i18n"
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+func TestMutuallyExclusive(t *testing.T) {
+	t.Parallel()
+
+	c := MutuallyExclusive("user", "role")
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, c(map[string]string{}))
+		assert.Nil(t, c(map[string]string{"user": "u", "silent": "silent"}))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		t.Parallel()
+
+		expect := plugin.NewArgumentErrorl(mutuallyExclusiveFlagsError.
+			WithPlaceholders(mutuallyExclusiveFlagsErrorPlaceholders{
+				First:  "u",
+				Second: "role",
+			}))
+
+		actual := c(map[string]string{"user": "u", "role": "role"})
+		assert.Equal(t, expect, actual)
+
+		desc, err := actual.Description(i18n.NewFallbackLocalizer())
+		require.NoError(t, err)
+		assert.Equal(t, "You can't use the `-u` and the `-role`-flag together.", desc)
+	})
+}
+
+func TestRequires(t *testing.T) {
+	t.Parallel()
+
+	c := Requires("silent", "reason")
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, c(map[string]string{}))
+		assert.Nil(t, c(map[string]string{"reason": "reason"}))
+		assert.Nil(t, c(map[string]string{"silent": "s", "reason": "reason"}))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		t.Parallel()
+
+		expect := plugin.NewArgumentErrorl(requiredFlagMissingError.
+			WithPlaceholders(requiredFlagMissingErrorPlaceholders{
+				Flag:     "s",
+				Required: "reason",
+			}))
+
+		actual := c(map[string]string{"silent": "s"})
+		assert.Equal(t, expect, actual)
+
+		desc, err := actual.Description(i18n.NewFallbackLocalizer())
+		require.NoError(t, err)
+		assert.Equal(t, "If you use the `-s`-flag, you also need to use the `-reason`-flag.", desc)
+	})
+}
+
+func TestAtLeastOneOf(t *testing.T) {
+	t.Parallel()
+
+	c := AtLeastOneOf("user", "role")
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, c(map[string]string{"role": "r"}))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		t.Parallel()
+
+		expect := plugin.NewArgumentErrorl(noneOfFlagsUsedError.
+			WithPlaceholders(noneOfFlagsUsedErrorPlaceholders{
+				Flags: "`-user`, `-role`",
+			}))
+
+		actual := c(map[string]string{"silent": "silent"})
+		assert.Equal(t, expect, actual)
+	})
+}
+
+func TestCheckFlagConstraints(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no constraints", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, CheckFlagConstraints(struct{ plugin.ArgConfig }{}, map[string]string{"abc": "abc"}))
+	})
+
+	t.Run("satisfied", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &Config{FlagConstraints: []FlagConstraint{AtLeastOneOf("abc", "def")}}
+		assert.NoError(t, CheckFlagConstraints(cfg, map[string]string{"abc": "abc"}))
+	})
+
+	t.Run("not satisfied", func(t *testing.T) {
+		t.Parallel()
+
+		c := AtLeastOneOf("abc", "def")
+		cfg := &LocalizedConfig{FlagConstraints: []FlagConstraint{c}}
+
+		expect := c(map[string]string{})
+
+		actual := CheckFlagConstraints(cfg, map[string]string{})
+		assert.Equal(t, expect, actual)
+	})
+}
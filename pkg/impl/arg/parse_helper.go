@@ -29,6 +29,16 @@ type parseHelper struct {
 
 	flags      plugin.Flags
 	multiFlags map[string]reflect.Value
+	// usedFlags maps the names of the flags that were used to the name or
+	// alias they were used with.
+	usedFlags map[string]string
+
+	// flagConstraints are the constraints the used flags must satisfy.
+	flagConstraints []FlagConstraint
+
+	// prompt is used to prompt for missing required arguments.
+	// If it is nil, missing arguments result in an error.
+	prompt *Prompt
 }
 
 //nolint:dupl
@@ -47,6 +57,10 @@ func newParseHelper(
 		flags:    make(plugin.Flags, len(flags)),
 	}
 
+	if len(flags) > 0 {
+		p.usedFlags = make(map[string]string, len(flags))
+	}
+
 	var numMultiFlags int
 
 	for _, f := range flags {
@@ -64,6 +78,18 @@ func newParseHelper(
 
 // store stores the parsed arguments in the context.
 func (h *parseHelper) store() error {
+	for _, c := range h.flagConstraints {
+		if err := c(h.usedFlags); err != nil {
+			return err
+		}
+	}
+
+	if h.prompt != nil {
+		if err := h.promptMissingArgs(); err != nil {
+			return err
+		}
+	}
+
 	if h.variadicSlice.IsValid() {
 		h.args = append(h.args, h.variadicSlice.Interface())
 	}
@@ -182,6 +208,8 @@ func (h *parseHelper) flag(name string) plugin.Flag {
 }
 
 func (h *parseHelper) addFlag(flag plugin.Flag, usedName, content string) (err error) {
+	h.usedFlags[flag.GetName()] = usedName
+
 	var val interface{}
 
 	if flag.GetType() == Switch {
@@ -197,7 +225,7 @@ func (h *parseHelper) addFlag(flag plugin.Flag, usedName, content string) (err e
 
 		val, err = flag.GetType().Parse(h.state, ctx)
 		if err != nil {
-			return err
+			return withSuggestions(h.state, flag.GetType(), ctx, err)
 		}
 	}
 
@@ -302,7 +330,7 @@ func (h *parseHelper) addArg(content string) error {
 
 	val, err := typ.Parse(h.state, ctx)
 	if err != nil {
-		return err
+		return withSuggestions(h.state, typ, ctx, err)
 	}
 
 	if !variadic {
@@ -28,6 +28,27 @@ var (
 		"arg.parser.error.group_not_closed", "You need to close the {{.quote}}.")
 )
 
+var (
+	mutuallyExclusiveFlagsError = i18n.NewFallbackConfig(
+		"arg.parser.error.mutually_exclusive_flags",
+		"You can't use the `-{{.first}}` and the `-{{.second}}`-flag together.")
+
+	requiredFlagMissingError = i18n.NewFallbackConfig(
+		"arg.parser.error.required_flag_missing",
+		"If you use the `-{{.flag}}`-flag, you also need to use the `-{{.required}}`-flag.")
+
+	noneOfFlagsUsedError = i18n.NewFallbackConfig(
+		"arg.parser.error.none_of_flags_used", "You need to use at least one of the flags {{.flags}}.")
+)
+
+var (
+	missingArgPrompt = i18n.NewFallbackConfig(
+		"arg.parser.prompt.missing_arg", "Please enter the {{.name}} ({{.type}}).")
+
+	missingArgRetryPrompt = i18n.NewFallbackConfig(
+		"arg.parser.prompt.missing_arg_retry", "{{.error}}\nPlease enter the {{.name}} ({{.type}}) again.")
+)
+
 type (
 	unknownFlagErrorPlaceholders struct {
 		Name string
@@ -48,4 +69,29 @@ type (
 	groupNotClosedErrorPlaceholders struct {
 		Quote string
 	}
+
+	mutuallyExclusiveFlagsErrorPlaceholders struct {
+		First  string
+		Second string
+	}
+
+	requiredFlagMissingErrorPlaceholders struct {
+		Flag     string
+		Required string
+	}
+
+	noneOfFlagsUsedErrorPlaceholders struct {
+		Flags string
+	}
+
+	missingArgPromptPlaceholders struct {
+		Name string
+		Type string
+	}
+
+	missingArgRetryPromptPlaceholders struct {
+		Error string
+		Name  string
+		Type  string
+	}
 )
@@ -18,6 +18,8 @@ var Command plugin.ArgType = new(commandType)
 
 type commandType struct{}
 
+var _ plugin.CompletingArgType = new(commandType)
+
 func (c commandType) GetName(l *i18n.Localizer) string {
 	name, _ := l.Localize(commandName) // we have a fallback
 	return name
@@ -41,10 +43,38 @@ func (c commandType) Parse(_ *state.State, ctx *plugin.ParseContext) (interface{
 	return nil, newArgumentError(commandNotFoundError, ctx, nil)
 }
 
+// Complete returns the invokes of the commands matching the input.
+// Hidden commands are not completed.
+func (c commandType) Complete(_ *state.State, ctx *plugin.ParseContext, max int) ([]plugin.Completion, error) {
+	comp := newCompleter(ctx.Raw)
+	completeCommands(comp, ctx.Commands(), ctx.Modules())
+
+	return comp.completions(max), nil
+}
+
 func (c commandType) GetDefault() interface{} {
 	return (plugin.ResolvedCommand)(nil)
 }
 
+// completeCommands adds the passed commands and the commands of the passed
+// modules, that are not hidden, to the passed completer.
+func completeCommands(comp *completer, cmds []plugin.ResolvedCommand, mods []plugin.ResolvedModule) {
+	for _, cmd := range cmds {
+		if cmd.IsHidden() {
+			continue
+		}
+
+		invoke := cmd.ID().AsInvoke()
+		comp.add(plugin.Completion{Name: invoke, Value: invoke}, invoke)
+	}
+
+	for _, mod := range mods {
+		if !mod.IsHidden() {
+			completeCommands(comp, mod.Commands(), mod.Modules())
+		}
+	}
+}
+
 // =============================================================================
 // Module
 // =====================================================================================
@@ -56,6 +86,8 @@ var Module plugin.ArgType = new(moduleType)
 
 type moduleType struct{}
 
+var _ plugin.CompletingArgType = new(moduleType)
+
 func (m moduleType) GetName(l *i18n.Localizer) string {
 	name, _ := l.Localize(moduleName) // we have a fallback
 	return name
@@ -78,10 +110,34 @@ func (m moduleType) Parse(_ *state.State, ctx *plugin.ParseContext) (interface{}
 	return nil, newArgumentError(moduleNotFoundError, ctx, nil)
 }
 
+// Complete returns the invokes of the modules matching the input.
+// Hidden modules are not completed.
+func (m moduleType) Complete(_ *state.State, ctx *plugin.ParseContext, max int) ([]plugin.Completion, error) {
+	comp := newCompleter(ctx.Raw)
+	completeModules(comp, ctx.Modules())
+
+	return comp.completions(max), nil
+}
+
 func (m moduleType) GetDefault() interface{} {
 	return (plugin.ResolvedModule)(nil)
 }
 
+// completeModules adds the passed modules and their submodules, that are not
+// hidden, to the passed completer.
+func completeModules(comp *completer, mods []plugin.ResolvedModule) {
+	for _, mod := range mods {
+		if mod.IsHidden() {
+			continue
+		}
+
+		invoke := mod.ID().AsInvoke()
+		comp.add(plugin.Completion{Name: invoke, Value: invoke}, invoke)
+
+		completeModules(comp, mod.Modules())
+	}
+}
+
 // =============================================================================
 // Plugin
 // =====================================================================================
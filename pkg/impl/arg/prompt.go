@@ -0,0 +1,145 @@
+package arg
+
+import (
+	"context"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/mavolin/disstate/v4/pkg/event"
+	"github.com/mavolin/disstate/v4/pkg/state"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/i18n"
+	"github.com/mavolin/adam/pkg/plugin"
+	"github.com/mavolin/adam/pkg/utils/msgbuilder"
+)
+
+// Prompt is used to ask the user for the required arguments they didn't
+// specify, instead of failing with an error.
+//
+// The user is asked for each missing argument in turn.
+// Their reply is parsed using the plugin.ArgType of the argument, as if it
+// had been part of the invoke.
+// If parsing fails, the user is informed about the error and asked again.
+type Prompt struct {
+	// MaxAttempts is the maximum number of replies that are parsed for a
+	// single argument.
+	// If the last of those replies can't be parsed either, the error that
+	// occurred during parsing is returned.
+	//
+	// Default: 3
+	MaxAttempts int
+	// Timeout is the time the user has to reply to a prompt.
+	// If the user does not reply in time, a *msgbuilder.TimeoutError is
+	// returned.
+	//
+	// Default: 30 * time.Second
+	Timeout time.Duration
+}
+
+func (p *Prompt) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return 3
+	}
+
+	return p.MaxAttempts
+}
+
+func (p *Prompt) timeout() time.Duration {
+	if p.Timeout <= 0 {
+		return 30 * time.Second
+	}
+
+	return p.Timeout
+}
+
+// promptMissingArgs prompts the user for all required arguments that weren't
+// parsed yet.
+func (h *parseHelper) promptMissingArgs() error {
+	for h.argIndex < len(h.rargData) {
+		if err := h.promptArg(h.rargData[h.argIndex]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// promptArg prompts the user for the passed argument, until they either
+// reply with a parsable value or run out of attempts.
+func (h *parseHelper) promptArg(arg plugin.RequiredArg) (err error) {
+	name := arg.GetName(h.ctx.Localizer)
+	typeName := arg.GetType().GetName(h.ctx.Localizer)
+
+	content := missingArgPrompt.
+		WithPlaceholders(missingArgPromptPlaceholders{
+			Name: name,
+			Type: typeName,
+		})
+
+	for i := 0; i < h.prompt.maxAttempts(); i++ {
+		var reply *discord.Message
+
+		reply, err = h.promptReply(content)
+		if err != nil {
+			return err
+		}
+
+		if err = h.addArg(reply.Content); err == nil {
+			return nil
+		}
+
+		var aerr *plugin.ArgumentError
+		if !errors.As(err, &aerr) {
+			return err
+		}
+
+		desc, lerr := aerr.Description(h.ctx.Localizer)
+		if lerr != nil {
+			return err
+		}
+
+		content = missingArgRetryPrompt.
+			WithPlaceholders(missingArgRetryPromptPlaceholders{
+				Error: desc,
+				Name:  name,
+				Type:  typeName,
+			})
+	}
+
+	return err
+}
+
+// promptReply sends the passed prompt and waits for the user's reply.
+//
+// The reply is awaited before the prompt is sent, so that replies sent
+// immediately after the prompt aren't missed.
+func (h *parseHelper) promptReply(prompt *i18n.Config) (*discord.Message, error) {
+	replies := make(chan discord.Message, 1)
+
+	rm := h.state.AddHandler(func(_ *state.State, e *event.MessageCreate) {
+		if e.ChannelID != h.ctx.ChannelID || e.Author.ID != h.ctx.Author.ID {
+			return
+		}
+
+		select {
+		case replies <- e.Message:
+		default: // there already is a reply
+		}
+	})
+	defer rm()
+
+	if _, err := msgbuilder.New(h.state, h.ctx).WithContentl(prompt).Reply(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.prompt.timeout())
+	defer cancel()
+
+	select {
+	case reply := <-replies:
+		return &reply, nil
+	case <-ctx.Done():
+		return nil, &msgbuilder.TimeoutError{UserID: h.ctx.Author.ID, Cause: ctx.Err()}
+	}
+}
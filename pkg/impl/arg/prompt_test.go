@@ -0,0 +1,165 @@
+package arg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/mavolin/disstate/v4/pkg/event"
+	"github.com/mavolin/disstate/v4/pkg/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/i18n"
+	"github.com/mavolin/adam/pkg/plugin"
+	"github.com/mavolin/adam/pkg/utils/msgbuilder"
+)
+
+// promptReplier is a plugin.Replier that answers the n-th prompt it sends
+// with the n-th of its replies.
+//
+// As the prompt starts awaiting the reply before sending the prompt, the
+// reply is dispatched right after the prompt was sent.
+type promptReplier struct {
+	s       *state.State
+	replies []string
+
+	// prompts are the contents of the prompts that were sent.
+	prompts []string
+}
+
+func newPromptReplier(s *state.State, replies ...string) *promptReplier {
+	return &promptReplier{s: s, replies: replies}
+}
+
+func (r *promptReplier) Reply(ctx *plugin.Context, data api.SendMessageData) (*discord.Message, error) {
+	r.prompts = append(r.prompts, data.Content)
+
+	i := len(r.prompts) - 1
+	if i < len(r.replies) {
+		r.s.Call(&event.MessageCreate{
+			Base: event.NewBase(),
+			MessageCreateEvent: &gateway.MessageCreateEvent{
+				Message: discord.Message{
+					ChannelID: ctx.ChannelID,
+					Author:    ctx.Author,
+					Content:   r.replies[i],
+				},
+			},
+		})
+	}
+
+	return &discord.Message{ID: discord.MessageID(i + 1), ChannelID: ctx.ChannelID}, nil
+}
+
+func (r *promptReplier) ReplyDM(*plugin.Context, api.SendMessageData) (*discord.Message, error) {
+	panic("not implemented")
+}
+
+func (r *promptReplier) Edit(
+	ctx *plugin.Context, messageID discord.MessageID, _ api.EditMessageData,
+) (*discord.Message, error) {
+	return &discord.Message{ID: messageID, ChannelID: ctx.ChannelID}, nil
+}
+
+func (r *promptReplier) EditDM(*plugin.Context, discord.MessageID, api.EditMessageData) (*discord.Message, error) {
+	panic("not implemented")
+}
+
+func TestPrompt(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{RequiredArgs: []RequiredArg{{Name: "number", Type: SimpleInteger}}}
+
+	newCtx := func(r plugin.Replier) *plugin.Context {
+		return &plugin.Context{
+			Base: event.NewBase(),
+			Message: discord.Message{
+				ChannelID: 123,
+				Author:    discord.User{ID: 456},
+			},
+			Localizer: i18n.NewFallbackLocalizer(),
+			Replier:   r,
+		}
+	}
+
+	expectPrompt := func(t *testing.T, l *i18n.Localizer) string {
+		prompt, err := l.Localize(missingArgPrompt.
+			WithPlaceholders(missingArgPromptPlaceholders{
+				Name: "number",
+				Type: SimpleInteger.GetName(l),
+			}))
+		require.NoError(t, err)
+
+		return prompt
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		_, s := state.NewMocker(t)
+
+		r := newPromptReplier(s, "12")
+		ctx := newCtx(r)
+
+		p := &DelimiterParser{Prompt: new(Prompt)}
+
+		err := p.Parse("", cfg, s, ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, plugin.Args{12}, ctx.Args)
+		assert.Equal(t, []string{expectPrompt(t, ctx.Localizer)}, r.prompts)
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		t.Parallel()
+
+		_, s := state.NewMocker(t)
+
+		r := newPromptReplier(s, "abc", "12")
+		ctx := newCtx(r)
+
+		p := NewPromptingShellwordParser(new(Prompt))
+
+		err := p.Parse("", cfg, s, ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, plugin.Args{12}, ctx.Args)
+
+		require.Len(t, r.prompts, 2)
+		assert.Equal(t, expectPrompt(t, ctx.Localizer), r.prompts[0])
+		assert.Contains(t, r.prompts[1], "`abc`")
+	})
+
+	t.Run("max attempts", func(t *testing.T) {
+		t.Parallel()
+
+		_, s := state.NewMocker(t)
+
+		r := newPromptReplier(s, "abc", "def", "12")
+		ctx := newCtx(r)
+
+		p := &DelimiterParser{Prompt: &Prompt{MaxAttempts: 2}}
+
+		err := p.Parse("", cfg, s, ctx)
+		assert.IsType(t, new(plugin.ArgumentError), err)
+		assert.Len(t, r.prompts, 2)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		_, s := state.NewMocker(t)
+
+		r := newPromptReplier(s)
+		ctx := newCtx(r)
+
+		p := &DelimiterParser{Prompt: &Prompt{Timeout: 10 * time.Millisecond}}
+
+		err := p.Parse("", cfg, s, ctx)
+		assert.IsType(t, new(msgbuilder.TimeoutError), err)
+		assert.Len(t, r.prompts, 1)
+	})
+}
@@ -29,6 +29,8 @@ var Role plugin.ArgType = new(role)
 
 type role struct{}
 
+var _ plugin.CompletingArgType = new(role)
+
 func (r role) GetName(l *i18n.Localizer) string {
 	name, _ := l.Localize(roleName) // we have a fallback
 	return name
@@ -89,6 +91,32 @@ func (r role) Parse(s *state.State, ctx *plugin.ParseContext) (interface{}, erro
 	return role, nil
 }
 
+// Complete returns the roles of the invoking guild matching the input.
+// The @everyone role is never completed.
+// In direct messages, Complete returns no Completions.
+func (r role) Complete(s *state.State, ctx *plugin.ParseContext, max int) ([]plugin.Completion, error) {
+	if !ctx.GuildID.IsValid() {
+		return nil, nil
+	}
+
+	roles, err := s.Roles(ctx.GuildID)
+	if err != nil {
+		return nil, err
+	}
+
+	comp := newCompleter(ctx.Raw)
+
+	for _, r := range roles {
+		if discord.GuildID(r.ID) == ctx.GuildID { // @everyone
+			continue
+		}
+
+		comp.add(plugin.Completion{Name: r.Name, Value: r.Mention()}, r.Name)
+	}
+
+	return comp.completions(max), nil
+}
+
 func (r role) GetDefault() interface{} {
 	return (*discord.Role)(nil)
 }
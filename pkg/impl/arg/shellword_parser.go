@@ -33,10 +33,24 @@ import (
 // literally to make usage easier for users unaware of escapes.
 var ShellwordParser plugin.ArgParser = new(shellwordParser)
 
-type shellwordParser struct{}
+// NewPromptingShellwordParser creates a new plugin.ArgParser that parses
+// arguments the same way as ShellwordParser does, but uses the passed
+// *Prompt to ask the user for missing required arguments.
+// If prompt is nil, the returned parser behaves like ShellwordParser.
+func NewPromptingShellwordParser(prompt *Prompt) plugin.ArgParser {
+	return &shellwordParser{prompt: prompt}
+}
+
+type shellwordParser struct {
+	prompt *Prompt
+}
 
 func (p *shellwordParser) Parse(args string, argConfig plugin.ArgConfig, s *state.State, ctx *plugin.Context) error {
-	return newShellwordParserState(args, argConfig, s, ctx).parse()
+	sp := newShellwordParserState(args, argConfig, s, ctx)
+	sp.helper.prompt = p.prompt
+	sp.helper.flagConstraints = flagConstraints(argConfig)
+
+	return sp.parse()
 }
 
 var shellwordEscapeReplacer = strings.NewReplacer(`"`, `\"`, `\`, `\\`)
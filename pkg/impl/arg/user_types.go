@@ -4,8 +4,10 @@ import (
 	"regexp"
 
 	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/state/store"
 	"github.com/mavolin/disstate/v4/pkg/state"
 
+	"github.com/mavolin/adam/pkg/errors"
 	"github.com/mavolin/adam/pkg/i18n"
 	"github.com/mavolin/adam/pkg/impl/restriction"
 	"github.com/mavolin/adam/pkg/plugin"
@@ -29,6 +31,8 @@ var User plugin.ArgType = new(user)
 
 type user struct{}
 
+var _ plugin.CompletingArgType = new(user)
+
 func (u user) GetName(l *i18n.Localizer) string {
 	name, _ := l.Localize(userName) // we have a fallback
 	return name
@@ -77,6 +81,12 @@ func (u user) Parse(s *state.State, ctx *plugin.ParseContext) (interface{}, erro
 	return user, nil
 }
 
+// Complete returns the members of the invoking guild matching the input.
+// In direct messages, Complete returns no Completions.
+func (u user) Complete(s *state.State, ctx *plugin.ParseContext, max int) ([]plugin.Completion, error) {
+	return completeMembers(s, ctx, max)
+}
+
 func (u user) GetDefault() interface{} {
 	return (*discord.User)(nil)
 }
@@ -97,6 +107,8 @@ var Member plugin.ArgType = new(member)
 
 type member struct{}
 
+var _ plugin.CompletingArgType = new(member)
+
 func (m member) GetName(l *i18n.Localizer) string {
 	name, _ := l.Localize(memberName) // we have a fallback
 	return name
@@ -162,6 +174,43 @@ func (m member) Parse(s *state.State, ctx *plugin.ParseContext) (interface{}, er
 	return member, nil
 }
 
+// Complete returns the members of the invoking guild, whose nickname,
+// username or tag match the input.
+// In direct messages, Complete returns no Completions.
+func (m member) Complete(s *state.State, ctx *plugin.ParseContext, max int) ([]plugin.Completion, error) {
+	return completeMembers(s, ctx, max)
+}
+
 func (m member) GetDefault() interface{} {
 	return (*discord.Member)(nil)
 }
+
+// completeMembers completes the members of the invoking guild.
+// To prevent fetching all members of a guild, only cached members are used.
+func completeMembers(s *state.State, ctx *plugin.ParseContext, max int) ([]plugin.Completion, error) {
+	if !ctx.GuildID.IsValid() {
+		return nil, nil
+	}
+
+	members, err := s.Cabinet.Members(ctx.GuildID)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	comp := newCompleter(ctx.Raw)
+
+	for _, m := range members {
+		tag := m.User.Tag()
+
+		names := []string{m.User.Username, tag}
+		if m.Nick != "" {
+			names = append(names, m.Nick)
+		}
+
+		comp.add(plugin.Completion{Name: tag, Value: m.User.Mention()}, names...)
+	}
+
+	return comp.completions(max), nil
+}
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/state/store"
+	"github.com/diamondburned/arikawa/v3/state/store/defaultstore"
 	"github.com/diamondburned/arikawa/v3/utils/httputil"
 	"github.com/mavolin/disstate/v4/pkg/state"
 	"github.com/stretchr/testify/assert"
@@ -348,3 +350,55 @@ func TestMember_Parse(t *testing.T) {
 		})
 	})
 }
+
+func TestMember_Complete(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cached", func(t *testing.T) {
+		t.Parallel()
+
+		_, s := state.NewMocker(t)
+
+		var guildID discord.GuildID = 123
+
+		s.Cabinet = &store.Cabinet{MemberStore: defaultstore.NewMember()}
+
+		err := s.Cabinet.MemberSet(guildID, discord.Member{
+			User: discord.User{ID: 456, Username: "abc", Discriminator: "0001"},
+		}, false)
+		require.NoError(t, err)
+
+		err = s.Cabinet.MemberSet(guildID, discord.Member{
+			User: discord.User{ID: 789, Username: "def", Discriminator: "0002"},
+		}, false)
+		require.NoError(t, err)
+
+		ctx := &plugin.ParseContext{
+			Context: &plugin.Context{Message: discord.Message{GuildID: guildID}},
+			Raw:     "ab",
+		}
+
+		expect := []plugin.Completion{{Name: "abc#0001", Value: "<@456>"}}
+
+		actual, err := Member.(plugin.CompletingArgType).Complete(s, ctx, 3)
+		require.NoError(t, err)
+		assert.Equal(t, expect, actual)
+	})
+
+	t.Run("not cached", func(t *testing.T) {
+		t.Parallel()
+
+		_, s := state.NewMocker(t)
+
+		s.Cabinet = &store.Cabinet{MemberStore: defaultstore.NewMember()}
+
+		ctx := &plugin.ParseContext{
+			Context: &plugin.Context{Message: discord.Message{GuildID: 123}},
+			Raw:     "ab",
+		}
+
+		actual, err := Member.(plugin.CompletingArgType).Complete(s, ctx, 3)
+		require.NoError(t, err)
+		assert.Nil(t, actual)
+	})
+}
@@ -0,0 +1,254 @@
+// Package cooldown provides a command that allows bot owners to inspect and
+// reset the cooldowns of other users.
+//
+// Only throttlers implementing throttler.Inspector, such as those created by
+// package throttler, can be inspected.
+package cooldown
+
+import (
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/mavolin/disstate/v4/pkg/state"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/i18n"
+	"github.com/mavolin/adam/pkg/impl/arg"
+	"github.com/mavolin/adam/pkg/impl/command"
+	"github.com/mavolin/adam/pkg/impl/restriction"
+	"github.com/mavolin/adam/pkg/impl/throttler"
+	"github.com/mavolin/adam/pkg/plugin"
+	"github.com/mavolin/adam/pkg/utils/duration"
+	"github.com/mavolin/adam/pkg/utils/msgbuilder"
+)
+
+// Cooldown is a hidden, bot owner-only command that shows the cooldown of a
+// user for a command, and optionally resets it or grants the user additional
+// uses.
+//
+// The cooldown of a command consists of the command's throttler and the
+// throttlers of its parent modules, each of which is displayed separately.
+type Cooldown struct {
+	command.LocalizedMeta
+}
+
+var _ plugin.Command = New()
+
+// New creates a new cooldown command.
+func New() *Cooldown {
+	return &Cooldown{
+		LocalizedMeta: command.LocalizedMeta{
+			Name:             "cooldown",
+			ShortDescription: shortDescription,
+			LongDescription:  longDescription,
+			ExampleArgs:      exampleArgs,
+			Args: &arg.LocalizedConfig{
+				RequiredArgs: []arg.LocalizedRequiredArg{
+					{
+						Name:        argCommandName,
+						Type:        arg.Command,
+						Description: argCommandDescription,
+					},
+					{
+						Name:        argUserName,
+						Type:        arg.User,
+						Description: argUserDescription,
+					},
+				},
+				Flags: []arg.LocalizedFlag{
+					{
+						Name:        "reset",
+						Aliases:     []string{"r"},
+						Type:        arg.Switch,
+						Description: flagResetDescription,
+					},
+					{
+						Name:        "grant",
+						Aliases:     []string{"g"},
+						Type:        arg.IntegerWithMin(0),
+						Description: flagGrantDescription,
+					},
+					{
+						Name:        "channel",
+						Aliases:     []string{"c"},
+						Type:        arg.SimpleNumericID,
+						Description: flagChannelDescription,
+					},
+					{
+						Name:        "guild",
+						Type:        arg.SimpleNumericID,
+						Description: flagGuildDescription,
+					},
+					{
+						Name:        "dm",
+						Aliases:     []string{"d"},
+						Type:        arg.Switch,
+						Description: flagDMDescription,
+					},
+				},
+				FlagConstraints: []arg.FlagConstraint{arg.MutuallyExclusive("channel", "guild", "dm")},
+			},
+			Hidden:         true,
+			BotPermissions: discord.PermissionSendMessages,
+			Restrictions:   restriction.BotOwner,
+		},
+	}
+}
+
+func (c *Cooldown) Invoke(s *state.State, ctx *plugin.Context) (interface{}, error) {
+	rcmd := ctx.Args[0].(plugin.ResolvedCommand)
+	user := ctx.Args[1].(*discord.User)
+
+	throttlers := throttlersOf(rcmd)
+	if len(throttlers) == 0 {
+		return nil, errors.NewUserErrorl(notThrottledError.
+			WithPlaceholders(notThrottledErrorPlaceholders{
+				Command: rcmd.ID().AsInvoke(),
+			}))
+	}
+
+	target, err := targetOf(s, ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	eb := msgbuilder.NewEmbed().
+		WithTitlel(title.
+			WithPlaceholders(titlePlaceholders{
+				Command: rcmd.ID().AsInvoke(),
+			}))
+
+	for _, t := range throttlers {
+		u, err := update(t.throttler, target, ctx.Flags.Bool("reset"), uint(ctx.Flags.Int("grant")))
+		if errors.Is(err, throttler.ErrNotInspectable) {
+			eb.WithFieldl(t.name, notInspectableValue)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		if u.ResetIn <= 0 {
+			eb.WithFieldl(t.name, usageValueReset.
+				WithPlaceholders(usageValuePlaceholders{
+					Remaining: u.Remaining,
+				}))
+		} else {
+			eb.WithFieldl(t.name, usageValue.
+				WithPlaceholders(usageValuePlaceholders{
+					Remaining: u.Remaining,
+					ResetIn:   duration.Format(u.ResetIn.Round(time.Second)),
+				}))
+		}
+	}
+
+	return eb, nil
+}
+
+// targetOf returns the throttler.Target of the user with the passed id, as
+// selected by the flags of the invoke.
+//
+// The ids are the same ones the throttlers would see, if the user invoked in
+// the selected channel or guild.
+// That means, if the dm flag is set, or if the command is invoked in a direct
+// message without a channel or guild flag, the direct message channel of the
+// user and a GuildID of 0 are used.
+// If the guild flag is set and the current channel is on another guild, the
+// ChannelID is 0.
+func targetOf(s *state.State, ctx *plugin.Context, userID discord.UserID) (throttler.Target, error) {
+	target := throttler.Target{UserID: userID, ChannelID: ctx.ChannelID, GuildID: ctx.GuildID}
+
+	dm, _ := ctx.Flags["dm"].(bool)
+	channelID, _ := ctx.Flags["channel"].(uint64)
+	guildID, _ := ctx.Flags["guild"].(uint64)
+
+	switch {
+	case channelID > 0:
+		ch, err := s.Channel(discord.ChannelID(channelID))
+		if err != nil {
+			return target, errors.NewUserErrorl(channelNotFoundError)
+		}
+
+		target.ChannelID = ch.ID
+		target.GuildID = ch.GuildID
+	case guildID > 0:
+		if discord.GuildID(guildID) != ctx.GuildID {
+			target.ChannelID = 0
+		}
+
+		target.GuildID = discord.GuildID(guildID)
+	case dm || ctx.GuildID == 0:
+		ch, err := s.CreatePrivateChannel(userID)
+		if err != nil {
+			return target, errors.WithStack(err)
+		}
+
+		target.ChannelID = ch.ID
+		target.GuildID = 0
+	}
+
+	return target, nil
+}
+
+type namedThrottler struct {
+	name      *i18n.Config
+	throttler plugin.Throttler
+}
+
+// throttlersOf returns the throttlers of the parent modules of the passed
+// command, starting with the most distant one, followed by the command's
+// own throttler.
+func throttlersOf(rcmd plugin.ResolvedCommand) []namedThrottler {
+	var (
+		throttlers []namedThrottler
+		names      []string
+	)
+
+	for _, parent := range rcmd.SourceParents() {
+		names = append(names, parent.GetName())
+
+		mt, ok := parent.(plugin.ModuleThrottler)
+		if !ok {
+			continue
+		}
+
+		if t := mt.GetThrottler(); t != nil {
+			throttlers = append(throttlers, namedThrottler{
+				name: moduleThrottlerName.
+					WithPlaceholders(moduleThrottlerNamePlaceholders{
+						Module: strings.Join(names, " "),
+					}),
+				throttler: t,
+			})
+		}
+	}
+
+	if t := rcmd.Source().GetThrottler(); t != nil {
+		throttlers = append(throttlers, namedThrottler{name: commandThrottlerName, throttler: t})
+	}
+
+	return throttlers
+}
+
+// update applies the passed changes to the passed throttler and returns the
+// updated throttler.Usage of the passed target.
+// If the throttler is not a throttler.Inspector, update returns
+// throttler.ErrNotInspectable.
+func update(t plugin.Throttler, target throttler.Target, reset bool, grant uint) (*throttler.Usage, error) {
+	i, ok := t.(throttler.Inspector)
+	if !ok {
+		return nil, throttler.ErrNotInspectable
+	}
+
+	if reset {
+		if err := i.Reset(target); err != nil {
+			return nil, err
+		}
+	} else if grant > 0 {
+		if err := i.Grant(target, grant); err != nil {
+			return nil, err
+		}
+	}
+
+	return i.Usage(target)
+}
@@ -0,0 +1,198 @@
+package cooldown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/mavolin/disstate/v4/pkg/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/i18n"
+	"github.com/mavolin/adam/pkg/impl/throttler"
+	"github.com/mavolin/adam/pkg/plugin"
+	"github.com/mavolin/adam/pkg/utils/mock"
+	"github.com/mavolin/adam/pkg/utils/msgbuilder"
+)
+
+func TestCooldown_Invoke(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not throttled", func(t *testing.T) {
+		t.Parallel()
+
+		rcmd := mock.ResolveCommand(plugin.BuiltInSource, mock.Command{Name: "abc"})
+
+		ctx := &plugin.Context{
+			Localizer: i18n.NewFallbackLocalizer(),
+			Args:      plugin.Args{rcmd, &discord.User{ID: 123}},
+			Flags:     plugin.Flags{"reset": false, "grant": 0},
+		}
+
+		_, err := New().Invoke(nil, ctx)
+		assert.IsType(t, new(errors.UserError), err)
+	})
+
+	t.Run("throttled", func(t *testing.T) {
+		t.Parallel()
+
+		rmod := mock.ResolveModule(plugin.BuiltInSource, mock.Module{
+			Name:      "mod",
+			Throttler: throttler.PerUser(2, time.Minute),
+			Commands: []plugin.Command{
+				mock.Command{Name: "abc", Throttler: mock.NewThrottler(nil)},
+			},
+		})
+
+		rcmd := rmod.FindCommand("abc")
+		require.NotNil(t, rcmd)
+
+		invokeCtx := &plugin.Context{Message: discord.Message{Author: discord.User{ID: 123}}}
+
+		_, err := rcmd.Throttler().Check(nil, invokeCtx)
+		require.NoError(t, err)
+
+		ctx := &plugin.Context{
+			Message:   discord.Message{GuildID: 1},
+			Localizer: i18n.NewFallbackLocalizer(),
+			Args:      plugin.Args{rcmd, &discord.User{ID: 123}},
+			Flags:     plugin.Flags{"reset": false, "grant": 0},
+		}
+
+		actual, err := New().Invoke(nil, ctx)
+		require.NoError(t, err)
+
+		e, err := actual.(*msgbuilder.EmbedBuilder).Build(ctx.Localizer)
+		require.NoError(t, err)
+
+		expect := []discord.EmbedField{
+			{Name: "Module `mod`", Value: "Remaining uses: 1\nResets in: 1min"},
+			{Name: "Command", Value: "This cooldown can't be inspected."},
+		}
+		assert.Equal(t, expect, e.Fields)
+
+		ctx.Flags["reset"] = true
+
+		actual, err = New().Invoke(nil, ctx)
+		require.NoError(t, err)
+
+		e, err = actual.(*msgbuilder.EmbedBuilder).Build(ctx.Localizer)
+		require.NoError(t, err)
+
+		require.NotEmpty(t, e.Fields)
+		assert.Equal(t, "Remaining uses: 2", e.Fields[0].Value)
+	})
+
+	t.Run("dm", func(t *testing.T) {
+		t.Parallel()
+
+		m, s := state.NewMocker(t)
+
+		rcmd := mock.ResolveCommand(plugin.BuiltInSource, mock.Command{
+			Name:      "abc",
+			Throttler: throttler.PerChannel(2, time.Minute),
+		})
+
+		invokeCtx := &plugin.Context{
+			Message: discord.Message{ChannelID: 456, Author: discord.User{ID: 123}},
+		}
+
+		_, err := rcmd.Throttler().Check(nil, invokeCtx)
+		require.NoError(t, err)
+
+		m.CreatePrivateChannel(discord.Channel{
+			ID:           456,
+			DMRecipients: []discord.User{{ID: 123}},
+		})
+
+		ctx := &plugin.Context{
+			Message:   discord.Message{ChannelID: 789},
+			Localizer: i18n.NewFallbackLocalizer(),
+			Args:      plugin.Args{rcmd, &discord.User{ID: 123}},
+			Flags:     plugin.Flags{"reset": false, "grant": 0},
+		}
+
+		actual, err := New().Invoke(s, ctx)
+		require.NoError(t, err)
+
+		e, err := actual.(*msgbuilder.EmbedBuilder).Build(ctx.Localizer)
+		require.NoError(t, err)
+
+		require.Len(t, e.Fields, 1)
+		assert.Equal(t, "Remaining uses: 1\nResets in: 1min", e.Fields[0].Value)
+	})
+}
+
+func Test_targetOf(t *testing.T) {
+	t.Parallel()
+
+	t.Run("current", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := &plugin.Context{Message: discord.Message{ChannelID: 456, GuildID: 789}}
+
+		expect := throttler.Target{UserID: 123, ChannelID: 456, GuildID: 789}
+
+		actual, err := targetOf(nil, ctx, 123)
+		require.NoError(t, err)
+		assert.Equal(t, expect, actual)
+	})
+
+	t.Run("channel", func(t *testing.T) {
+		t.Parallel()
+
+		m, s := state.NewMocker(t)
+
+		ctx := &plugin.Context{
+			Message: discord.Message{ChannelID: 456, GuildID: 789},
+			Flags:   plugin.Flags{"channel": uint64(12)},
+		}
+
+		m.Channel(discord.Channel{ID: 12, GuildID: 345})
+
+		expect := throttler.Target{UserID: 123, ChannelID: 12, GuildID: 345}
+
+		actual, err := targetOf(s, ctx, 123)
+		require.NoError(t, err)
+		assert.Equal(t, expect, actual)
+	})
+
+	t.Run("guild", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := &plugin.Context{
+			Message: discord.Message{ChannelID: 456, GuildID: 789},
+			Flags:   plugin.Flags{"guild": uint64(345)},
+		}
+
+		expect := throttler.Target{UserID: 123, GuildID: 345}
+
+		actual, err := targetOf(nil, ctx, 123)
+		require.NoError(t, err)
+		assert.Equal(t, expect, actual)
+	})
+
+	t.Run("dm flag", func(t *testing.T) {
+		t.Parallel()
+
+		m, s := state.NewMocker(t)
+
+		ctx := &plugin.Context{
+			Message: discord.Message{ChannelID: 456, GuildID: 789},
+			Flags:   plugin.Flags{"dm": true},
+		}
+
+		m.CreatePrivateChannel(discord.Channel{
+			ID:           345,
+			DMRecipients: []discord.User{{ID: 123}},
+		})
+
+		expect := throttler.Target{UserID: 123, ChannelID: 345}
+
+		actual, err := targetOf(s, ctx, 123)
+		require.NoError(t, err)
+		assert.Equal(t, expect, actual)
+	})
+}
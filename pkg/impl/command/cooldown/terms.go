@@ -0,0 +1,132 @@
+package cooldown
+
+import (
+	"github.com/mavolin/adam/pkg/i18n"
+	"github.com/mavolin/adam/pkg/impl/command"
+)
+
+// =============================================================================
+// Meta
+// =====================================================================================
+
+var (
+	shortDescription = i18n.NewFallbackConfig(
+		"plugin.cooldown.short_description",
+		"Shows or resets the cooldown of a user for a command.")
+
+	longDescription = i18n.NewFallbackConfig(
+		"plugin.cooldown.long_description",
+		"Shows how many uses of a command a user has left, and when their cooldown resets.\n"+
+			"Use the `reset` flag to clear the cooldown of the user, "+
+			"or the `grant` flag to give them back some of their uses.")
+
+	exampleArgs = command.LocalizedExampleArgs{
+		{
+			Args: []*i18n.Config{
+				i18n.NewFallbackConfig("plugin.cooldown.example.show.arg.0", "some_command"),
+				i18n.NewFallbackConfig("plugin.cooldown.example.show.arg.1", "@user"),
+			},
+		},
+		{
+			Args: []*i18n.Config{
+				i18n.NewFallbackConfig("plugin.cooldown.example.grant.arg.0", "some_command"),
+				i18n.NewFallbackConfig("plugin.cooldown.example.grant.arg.1", "@user"),
+			},
+			Flags: map[string]*i18n.Config{
+				"grant": i18n.NewFallbackConfig("plugin.cooldown.example.grant.flag.grant", "2"),
+			},
+		},
+	}
+)
+
+// =============================================================================
+// Arguments
+// =====================================================================================
+
+var (
+	argCommandName        = i18n.NewFallbackConfig("plugin.cooldown.arg.command.name", "Command")
+	argCommandDescription = i18n.NewFallbackConfig(
+		"plugin.cooldown.arg.command.description",
+		"The command whose cooldown you want to see.")
+
+	argUserName        = i18n.NewFallbackConfig("plugin.cooldown.arg.user.name", "User")
+	argUserDescription = i18n.NewFallbackConfig(
+		"plugin.cooldown.arg.user.description",
+		"The user whose cooldown you want to see.")
+
+	flagResetDescription = i18n.NewFallbackConfig(
+		"plugin.cooldown.flag.reset.description",
+		"Resets the cooldown of the user.")
+
+	flagGrantDescription = i18n.NewFallbackConfig(
+		"plugin.cooldown.flag.grant.description",
+		"Gives the user back the specified number of uses.")
+
+	flagChannelDescription = i18n.NewFallbackConfig(
+		"plugin.cooldown.flag.channel.description",
+		"The id of the channel whose cooldowns you want to see. Defaults to the current channel.")
+
+	flagGuildDescription = i18n.NewFallbackConfig(
+		"plugin.cooldown.flag.guild.description",
+		"The id of the server whose cooldowns you want to see. Defaults to the current server.")
+
+	flagDMDescription = i18n.NewFallbackConfig(
+		"plugin.cooldown.flag.dm.description",
+		"Shows the cooldowns of the user in their direct messages. "+
+			"This is the default, if used in a direct message.")
+)
+
+// =============================================================================
+// Response
+// =====================================================================================
+
+var (
+	title = i18n.NewFallbackConfig("plugin.cooldown.title", "Cooldown of `{{.command}}`")
+
+	commandThrottlerName = i18n.NewFallbackConfig("plugin.cooldown.throttler.command", "Command")
+	moduleThrottlerName  = i18n.NewFallbackConfig("plugin.cooldown.throttler.module", "Module `{{.module}}`")
+
+	usageValue = i18n.NewFallbackConfig(
+		"plugin.cooldown.usage",
+		"Remaining uses: {{.remaining}}\n"+
+			"Resets in: {{.reset_in}}")
+
+	usageValueReset = i18n.NewFallbackConfig(
+		"plugin.cooldown.usage.reset",
+		"Remaining uses: {{.remaining}}")
+
+	notInspectableValue = i18n.NewFallbackConfig(
+		"plugin.cooldown.not_inspectable",
+		"This cooldown can't be inspected.")
+)
+
+type (
+	titlePlaceholders struct {
+		Command string
+	}
+
+	moduleThrottlerNamePlaceholders struct {
+		Module string
+	}
+
+	usageValuePlaceholders struct {
+		Remaining uint
+		ResetIn   string
+	}
+)
+
+// =============================================================================
+// Errors
+// =====================================================================================
+
+var notThrottledError = i18n.NewFallbackConfig(
+	"plugin.cooldown.error.not_throttled",
+	"`{{.command}}` has no cooldown.")
+
+type notThrottledErrorPlaceholders struct {
+	Command string
+}
+
+var channelNotFoundError = i18n.NewFallbackConfig(
+	"plugin.cooldown.error.channel_not_found",
+	"I can't find a channel with that id.")
@@ -1,6 +1,8 @@
 package command
 
 import (
+	"time"
+
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/mavolin/disstate/v4/pkg/state"
 
@@ -45,9 +47,17 @@ type LocalizedMeta struct {
 	Restrictions plugin.RestrictionFunc
 	// Throttler is the optional plugin.Throttler of the command.
 	Throttler plugin.Throttler
+	// Timeout is the optional maximum duration an invoke of the command may
+	// take.
+	// After the timeout expires, the context.Context found in
+	// plugin.Context.Ctx will be canceled.
+	Timeout time.Duration
 }
 
-var _ plugin.CommandMeta = LocalizedMeta{}
+var (
+	_ plugin.CommandMeta = LocalizedMeta{}
+	_ plugin.Timeouter   = LocalizedMeta{}
+)
 
 func (m LocalizedMeta) GetName() string      { return m.Name }
 func (m LocalizedMeta) GetAliases() []string { return m.Aliases }
@@ -93,6 +103,7 @@ func (m LocalizedMeta) IsRestricted(s *state.State, ctx *plugin.Context) error {
 }
 
 func (m LocalizedMeta) GetThrottler() plugin.Throttler { return m.Throttler }
+func (m LocalizedMeta) GetTimeout() time.Duration      { return m.Timeout }
 
 // =============================================================================
 // ExampleArgs
@@ -1,6 +1,8 @@
 package command
 
 import (
+	"time"
+
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/mavolin/disstate/v4/pkg/state"
 
@@ -45,9 +47,17 @@ type Meta struct {
 	Restrictions plugin.RestrictionFunc
 	// Throttler is the optional plugin.Throttler of the command.
 	Throttler plugin.Throttler
+	// Timeout is the optional maximum duration an invoke of the command may
+	// take.
+	// After the timeout expires, the context.Context found in
+	// plugin.Context.Ctx will be canceled.
+	Timeout time.Duration
 }
 
-var _ plugin.CommandMeta = Meta{}
+var (
+	_ plugin.CommandMeta = Meta{}
+	_ plugin.Timeouter   = Meta{}
+)
 
 func (m Meta) GetName() string                                   { return m.Name }
 func (m Meta) GetAliases() []string                              { return m.Aliases }
@@ -69,5 +79,6 @@ func (m Meta) IsRestricted(s *state.State, ctx *plugin.Context) error {
 }
 
 func (m Meta) GetThrottler() plugin.Throttler { return m.Throttler }
+func (m Meta) GetTimeout() time.Duration      { return m.Timeout }
 
 type ExampleArgs = plugin.ExampleArgs
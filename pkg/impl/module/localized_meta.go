@@ -1,6 +1,9 @@
 package module
 
 import (
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/mavolin/disstate/v4/pkg/state"
+
 	"github.com/mavolin/adam/pkg/i18n"
 	"github.com/mavolin/adam/pkg/plugin"
 )
@@ -15,9 +18,30 @@ type LocalizedMeta struct {
 	ShortDescription *i18n.Config
 	// LongDescription is an optional long description of the module.
 	LongDescription *i18n.Config
+
+	// ChannelTypes are the plugin.ChannelTypes the commands of the module
+	// may be executed in, unless they define their own.
+	//
+	// If this is not set, the ChannelTypes of the parent will be used.
+	ChannelTypes plugin.ChannelTypes
+	// BotPermissions are the permissions the bot needs to execute the
+	// commands of the module, in addition to their own.
+	BotPermissions discord.Permissions
+	// Restrictions contains the optional restrictions of the module.
+	// They apply to all commands of the module and its submodules.
+	Restrictions plugin.RestrictionFunc
+	// Throttler is the optional plugin.Throttler of the module.
+	// It applies to all commands of the module and its submodules.
+	Throttler plugin.Throttler
 }
 
-var _ plugin.ModuleMeta = LocalizedMeta{}
+var (
+	_ plugin.ModuleMeta            = LocalizedMeta{}
+	_ plugin.ModuleThrottler       = LocalizedMeta{}
+	_ plugin.ModuleChannelTyper    = LocalizedMeta{}
+	_ plugin.ModuleBotPermissioner = LocalizedMeta{}
+	_ plugin.ModuleRestricter      = LocalizedMeta{}
+)
 
 func (m LocalizedMeta) GetName() string {
 	return m.Name
@@ -40,3 +64,23 @@ func (m LocalizedMeta) GetLongDescription(l *i18n.Localizer) string {
 
 	return desc
 }
+
+func (m LocalizedMeta) GetChannelTypes() plugin.ChannelTypes {
+	return m.ChannelTypes
+}
+
+func (m LocalizedMeta) GetBotPermissions() discord.Permissions {
+	return m.BotPermissions
+}
+
+func (m LocalizedMeta) IsRestricted(s *state.State, ctx *plugin.Context) error {
+	if m.Restrictions == nil {
+		return nil
+	}
+
+	return m.Restrictions(s, ctx)
+}
+
+func (m LocalizedMeta) GetThrottler() plugin.Throttler {
+	return m.Throttler
+}
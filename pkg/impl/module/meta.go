@@ -1,6 +1,9 @@
 package module
 
 import (
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/mavolin/disstate/v4/pkg/state"
+
 	"github.com/mavolin/adam/pkg/i18n"
 	"github.com/mavolin/adam/pkg/plugin"
 )
@@ -15,10 +18,43 @@ type Meta struct {
 	ShortDescription string
 	// LongDescription is an optional long description of the module.
 	LongDescription string
+
+	// ChannelTypes are the plugin.ChannelTypes the commands of the module
+	// may be executed in, unless they define their own.
+	//
+	// If this is not set, the ChannelTypes of the parent will be used.
+	ChannelTypes plugin.ChannelTypes
+	// BotPermissions are the permissions the bot needs to execute the
+	// commands of the module, in addition to their own.
+	BotPermissions discord.Permissions
+	// Restrictions contains the optional restrictions of the module.
+	// They apply to all commands of the module and its submodules.
+	Restrictions plugin.RestrictionFunc
+	// Throttler is the optional plugin.Throttler of the module.
+	// It applies to all commands of the module and its submodules.
+	Throttler plugin.Throttler
 }
 
-var _ plugin.ModuleMeta = Meta{}
+var (
+	_ plugin.ModuleMeta            = Meta{}
+	_ plugin.ModuleThrottler       = Meta{}
+	_ plugin.ModuleChannelTyper    = Meta{}
+	_ plugin.ModuleBotPermissioner = Meta{}
+	_ plugin.ModuleRestricter      = Meta{}
+)
 
 func (m Meta) GetName() string                            { return m.Name }
 func (m Meta) GetShortDescription(*i18n.Localizer) string { return m.ShortDescription }
 func (m Meta) GetLongDescription(*i18n.Localizer) string  { return m.LongDescription }
+func (m Meta) GetChannelTypes() plugin.ChannelTypes       { return m.ChannelTypes }
+func (m Meta) GetBotPermissions() discord.Permissions     { return m.BotPermissions }
+
+func (m Meta) IsRestricted(s *state.State, ctx *plugin.Context) error {
+	if m.Restrictions == nil {
+		return nil
+	}
+
+	return m.Restrictions(s, ctx)
+}
+
+func (m Meta) GetThrottler() plugin.Throttler { return m.Throttler }
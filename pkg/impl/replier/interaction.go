@@ -0,0 +1,220 @@
+package replier
+
+import (
+	"sync"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/mavolin/disstate/v4/pkg/state"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+// InteractionReplier is a plugin.Replier used for commands invoked through
+// an interaction.
+//
+// The first reply is sent as the initial response to the interaction, all
+// subsequent replies are sent as follow-up messages.
+// If the interaction was deferred using Defer, the first reply will replace
+// the loading state of the deferred response instead.
+//
+// Edits of the initial response and of follow-up messages are made through
+// the interaction, all other edits are made through the channel.
+// Direct messages are not part of the interaction and are sent just like
+// WrapState would send them.
+//
+// InteractionReplier is safe for concurrent use.
+type InteractionReplier struct {
+	s           *state.State
+	interaction *discord.InteractionEvent
+	dm          *wrappedReplier
+
+	mut       sync.Mutex
+	ephemeral bool
+	// responded specifies whether an initial response was sent, including
+	// deferred ones.
+	responded bool
+	// deferred specifies whether the initial response is a deferred response
+	// that has not yet been replaced.
+	deferred   bool
+	originalID discord.MessageID
+	followUps  map[discord.MessageID]struct{}
+}
+
+var _ plugin.Replier = new(InteractionReplier)
+
+// WrapInteraction creates a new *InteractionReplier replying to the passed
+// interaction.
+// If ephemeral is set to true, all replies will only be visible to the
+// invoking user, until changed using SetEphemeral.
+func WrapInteraction(s *state.State, e *discord.InteractionEvent, ephemeral bool) *InteractionReplier {
+	return &InteractionReplier{
+		s:           s,
+		interaction: e,
+		dm:          &wrappedReplier{s: s},
+		ephemeral:   ephemeral,
+	}
+}
+
+// SetEphemeral sets whether the replies sent from now on will only be
+// visible to the invoking user.
+//
+// As Discord applies the ephemeral flag of a deferred response to the reply
+// replacing it, SetEphemeral has no effect on that reply, if the interaction
+// was already deferred.
+func (r *InteractionReplier) SetEphemeral(ephemeral bool) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	r.ephemeral = ephemeral
+}
+
+// Defer acknowledges the interaction by sending a deferred response.
+// Discord will display a loading state, until the first reply is sent.
+//
+// Defer should be used by commands that require more than three seconds to
+// send their first reply.
+// If the interaction was already responded to, Defer is a no-op.
+func (r *InteractionReplier) Defer() error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if r.responded {
+		return nil
+	}
+
+	resp := api.InteractionResponse{Type: api.DeferredMessageInteractionWithSource}
+	if r.ephemeral {
+		resp.Data = &api.InteractionResponseData{Flags: api.EphemeralResponse}
+	}
+
+	if err := r.s.RespondInteraction(r.interaction.ID, r.interaction.Token, resp); err != nil {
+		return errors.WithStack(err)
+	}
+
+	r.responded = true
+	r.deferred = true
+
+	return nil
+}
+
+func (r *InteractionReplier) Reply(_ *plugin.Context, data api.SendMessageData) (*discord.Message, error) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	switch {
+	case !r.responded:
+		err := r.s.RespondInteraction(r.interaction.ID, r.interaction.Token, api.InteractionResponse{
+			Type: api.MessageInteractionWithSource,
+			Data: r.responseData(data),
+		})
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		r.responded = true
+
+		msg, err := r.s.InteractionResponse(r.interaction.AppID, r.interaction.Token)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		r.originalID = msg.ID
+
+		return msg, nil
+	case r.deferred:
+		msg, err := r.s.EditInteractionResponse(r.interaction.AppID, r.interaction.Token,
+			api.EditInteractionResponseData{
+				Content:         option.NewNullableString(data.Content),
+				Embeds:          &data.Embeds,
+				Components:      &data.Components,
+				AllowedMentions: data.AllowedMentions,
+				Files:           data.Files,
+			})
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		r.deferred = false
+		r.originalID = msg.ID
+
+		return msg, nil
+	default:
+		msg, err := r.s.FollowUpInteraction(r.interaction.AppID, r.interaction.Token, *r.responseData(data))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		if r.followUps == nil {
+			r.followUps = make(map[discord.MessageID]struct{}, 1)
+		}
+
+		r.followUps[msg.ID] = struct{}{}
+
+		return msg, nil
+	}
+}
+
+func (r *InteractionReplier) ReplyDM(ctx *plugin.Context, data api.SendMessageData) (*discord.Message, error) {
+	return r.dm.ReplyDM(ctx, data)
+}
+
+func (r *InteractionReplier) Edit(
+	ctx *plugin.Context, messageID discord.MessageID, data api.EditMessageData,
+) (*discord.Message, error) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	editData := api.EditInteractionResponseData{
+		Content:         data.Content,
+		Embeds:          data.Embeds,
+		Components:      data.Components,
+		AllowedMentions: data.AllowedMentions,
+		Files:           data.Files,
+	}
+
+	if messageID == r.originalID && r.originalID.IsValid() {
+		msg, err := r.s.EditInteractionResponse(r.interaction.AppID, r.interaction.Token, editData)
+		return msg, errors.WithStack(err)
+	}
+
+	if _, ok := r.followUps[messageID]; ok {
+		msg, err := r.s.EditInteractionFollowup(r.interaction.AppID, messageID, r.interaction.Token, editData)
+		return msg, errors.WithStack(err)
+	}
+
+	msg, err := r.s.EditMessageComplex(ctx.ChannelID, messageID, data)
+	return msg, errors.WithStack(err)
+}
+
+func (r *InteractionReplier) EditDM(
+	ctx *plugin.Context, messageID discord.MessageID, data api.EditMessageData,
+) (*discord.Message, error) {
+	return r.dm.EditDM(ctx, messageID, data)
+}
+
+// responseData converts the passed api.SendMessageData to
+// *api.InteractionResponseData, applying the ephemeral flag if set.
+func (r *InteractionReplier) responseData(data api.SendMessageData) *api.InteractionResponseData {
+	respData := &api.InteractionResponseData{
+		Content:         option.NewNullableString(data.Content),
+		AllowedMentions: data.AllowedMentions,
+		Files:           data.Files,
+	}
+
+	if len(data.Embeds) > 0 {
+		respData.Embeds = &data.Embeds
+	}
+
+	if len(data.Components) > 0 {
+		respData.Components = &data.Components
+	}
+
+	if r.ephemeral {
+		respData.Flags = api.EphemeralResponse
+	}
+
+	return respData
+}
@@ -0,0 +1,234 @@
+package replier
+
+import (
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/mavolin/disstate/v4/pkg/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+func TestInteractionReplier_responseData(t *testing.T) {
+	t.Parallel()
+
+	t.Run("content", func(t *testing.T) {
+		t.Parallel()
+
+		r := WrapInteraction(nil, nil, false)
+
+		expect := &api.InteractionResponseData{Content: option.NewNullableString("abc")}
+
+		actual := r.responseData(api.SendMessageData{Content: "abc"})
+		assert.Equal(t, expect, actual)
+	})
+
+	t.Run("embeds", func(t *testing.T) {
+		t.Parallel()
+
+		r := WrapInteraction(nil, nil, false)
+
+		embeds := []discord.Embed{{Title: "abc"}}
+
+		expect := &api.InteractionResponseData{
+			Content: option.NewNullableString(""),
+			Embeds:  &embeds,
+		}
+
+		actual := r.responseData(api.SendMessageData{Embeds: embeds})
+		assert.Equal(t, expect, actual)
+	})
+
+	t.Run("ephemeral", func(t *testing.T) {
+		t.Parallel()
+
+		r := WrapInteraction(nil, nil, false)
+		r.SetEphemeral(true)
+
+		expect := &api.InteractionResponseData{
+			Content: option.NewNullableString("abc"),
+			Flags:   api.EphemeralResponse,
+		}
+
+		actual := r.responseData(api.SendMessageData{Content: "abc"})
+		assert.Equal(t, expect, actual)
+	})
+}
+
+func TestInteractionReplier_Reply(t *testing.T) {
+	t.Parallel()
+
+	e := &discord.InteractionEvent{ID: 123, AppID: 456, Token: "abc"}
+
+	t.Run("initial response", func(t *testing.T) {
+		t.Parallel()
+
+		m, s := state.NewMocker(t)
+
+		r := WrapInteraction(s, e, false)
+
+		data := api.SendMessageData{Content: "abc"}
+		expect := discord.Message{ID: 789, Content: "abc"}
+
+		m.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+			Type: api.MessageInteractionWithSource,
+			Data: r.responseData(data),
+		})
+		m.InteractionResponse(e.AppID, e.Token, expect)
+
+		actual, err := r.Reply(nil, data)
+		require.NoError(t, err)
+		assert.Equal(t, expect, *actual)
+		assert.Equal(t, expect.ID, r.originalID)
+	})
+
+	t.Run("follow-up", func(t *testing.T) {
+		t.Parallel()
+
+		m, s := state.NewMocker(t)
+
+		r := WrapInteraction(s, e, false)
+		r.responded = true
+
+		data := api.SendMessageData{Content: "abc"}
+		expect := discord.Message{ID: 789, Content: "abc"}
+
+		m.FollowUpInteraction(e.AppID, e.Token, *r.responseData(data), expect)
+
+		actual, err := r.Reply(nil, data)
+		require.NoError(t, err)
+		assert.Equal(t, expect, *actual)
+		assert.Contains(t, r.followUps, expect.ID)
+	})
+
+	t.Run("deferred", func(t *testing.T) {
+		t.Parallel()
+
+		m, s := state.NewMocker(t)
+
+		r := WrapInteraction(s, e, false)
+
+		data := api.SendMessageData{Content: "abc"}
+		expect := discord.Message{ID: 789, Content: "abc"}
+
+		m.RespondInteraction(e.ID, e.Token, api.InteractionResponse{
+			Type: api.DeferredMessageInteractionWithSource,
+		})
+		m.EditInteractionResponse(e.AppID, e.Token, api.EditInteractionResponseData{
+			Content:    option.NewNullableString(data.Content),
+			Embeds:     &data.Embeds,
+			Components: &data.Components,
+		}, expect)
+
+		require.NoError(t, r.Defer())
+		// deferring twice should be a no-op
+		require.NoError(t, r.Defer())
+
+		actual, err := r.Reply(nil, data)
+		require.NoError(t, err)
+		assert.Equal(t, expect, *actual)
+		assert.Equal(t, expect.ID, r.originalID)
+		assert.False(t, r.deferred)
+	})
+}
+
+func TestInteractionReplier_ReplyDM(t *testing.T) {
+	t.Parallel()
+
+	m, s := state.NewMocker(t)
+
+	ctx := &plugin.Context{Message: discord.Message{Author: discord.User{ID: 123}}}
+
+	var dmID discord.ChannelID = 456
+
+	r := WrapInteraction(s, &discord.InteractionEvent{ID: 789, AppID: 12, Token: "abc"}, false)
+
+	data := api.SendMessageData{Content: "abc"}
+
+	expect := discord.Message{
+		ID:        34,
+		ChannelID: dmID,
+		Author:    ctx.Author,
+		Content:   data.Content,
+	}
+
+	m.CreatePrivateChannel(discord.Channel{
+		ID:           dmID,
+		DMRecipients: []discord.User{ctx.Author},
+	})
+	m.SendMessageComplex(data, expect)
+
+	actual, err := r.ReplyDM(ctx, data)
+	require.NoError(t, err)
+	assert.Equal(t, expect, *actual)
+}
+
+func TestInteractionReplier_Edit(t *testing.T) {
+	t.Parallel()
+
+	e := &discord.InteractionEvent{ID: 123, AppID: 456, Token: "abc"}
+
+	data := api.EditMessageData{Content: option.NewNullableString("abc")}
+
+	editData := api.EditInteractionResponseData{Content: data.Content}
+
+	t.Run("initial response", func(t *testing.T) {
+		t.Parallel()
+
+		m, s := state.NewMocker(t)
+
+		r := WrapInteraction(s, e, false)
+		r.responded = true
+		r.originalID = 789
+
+		expect := discord.Message{ID: r.originalID, Content: "abc"}
+
+		m.EditInteractionResponse(e.AppID, e.Token, editData, expect)
+
+		actual, err := r.Edit(nil, r.originalID, data)
+		require.NoError(t, err)
+		assert.Equal(t, expect, *actual)
+	})
+
+	t.Run("follow-up", func(t *testing.T) {
+		t.Parallel()
+
+		m, s := state.NewMocker(t)
+
+		r := WrapInteraction(s, e, false)
+		r.responded = true
+		r.originalID = 789
+		r.followUps = map[discord.MessageID]struct{}{12: {}}
+
+		expect := discord.Message{ID: 12, Content: "abc"}
+
+		m.EditInteractionFollowup(e.AppID, expect.ID, e.Token, editData, expect)
+
+		actual, err := r.Edit(nil, expect.ID, data)
+		require.NoError(t, err)
+		assert.Equal(t, expect, *actual)
+	})
+
+	t.Run("channel message", func(t *testing.T) {
+		t.Parallel()
+
+		m, s := state.NewMocker(t)
+
+		r := WrapInteraction(s, e, false)
+		r.responded = true
+
+		ctx := &plugin.Context{Message: discord.Message{ChannelID: 34}}
+
+		expect := discord.Message{ID: 56, ChannelID: ctx.ChannelID, Content: "abc"}
+
+		m.EditMessageComplex(data, expect)
+
+		actual, err := r.Edit(ctx, expect.ID, data)
+		require.NoError(t, err)
+		assert.Equal(t, expect, *actual)
+	})
+}
@@ -0,0 +1,53 @@
+package restriction
+
+import (
+	"time"
+
+	"github.com/mavolin/disstate/v4/pkg/state"
+
+	"github.com/mavolin/adam/pkg/i18n"
+	"github.com/mavolin/adam/pkg/plugin"
+	"github.com/mavolin/adam/pkg/utils/duration"
+)
+
+// AccountAge asserts that the account of the invoking user is at least
+// minAge old.
+// The creation date of the account is derived from the user's id.
+func AccountAge(minAge time.Duration) plugin.RestrictionFunc {
+	return func(_ *state.State, ctx *plugin.Context) error {
+		return assertAge(ctx.Author.ID.Time(), minAge, accountAgeError)
+	}
+}
+
+// MembershipAge asserts that the invoking user has been a member of the guild
+// for at least minAge.
+//
+// It fails if the command is used in a direct message.
+func MembershipAge(minAge time.Duration) plugin.RestrictionFunc {
+	return func(_ *state.State, ctx *plugin.Context) error {
+		if err := assertChannelTypes(ctx, plugin.GuildChannels); err != nil {
+			return err
+		}
+
+		return assertAge(ctx.Member.Joined.Time(), minAge, membershipAgeError)
+	}
+}
+
+// assertAge asserts that at least minAge has passed since the passed time.
+// If not, a *plugin.RestrictionError using the passed config is returned.
+func assertAge(since time.Time, minAge time.Duration, errConfig *i18n.Config) error {
+	remaining := minAge - time.Since(since)
+	if remaining <= 0 {
+		return nil
+	}
+
+	// round up to full seconds, so that we never tell the user to try again
+	// too early
+	remaining = (remaining + time.Second - 1).Truncate(time.Second)
+
+	return plugin.NewRestrictionErrorl(errConfig.
+		WithPlaceholders(&ageErrorPlaceholders{
+			MinAge:    duration.Format(minAge),
+			Remaining: duration.Format(remaining),
+		}))
+}
@@ -0,0 +1,115 @@
+package restriction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mavolin/adam/pkg/i18n"
+	"github.com/mavolin/adam/pkg/plugin"
+	"github.com/mavolin/adam/pkg/utils/mock"
+)
+
+func TestAccountAge(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		created  time.Time
+		expectOK bool
+	}{
+		{
+			name:     "old enough",
+			created:  time.Now().Add(-48 * time.Hour),
+			expectOK: true,
+		},
+		{
+			name:     "too young",
+			created:  time.Now().Add(-time.Hour),
+			expectOK: false,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := &plugin.Context{
+				Message: discord.Message{
+					Author: discord.User{ID: discord.UserID(discord.NewSnowflake(c.created))},
+				},
+			}
+
+			err := AccountAge(24*time.Hour)(nil, ctx)
+			if c.expectOK {
+				assert.NoError(t, err)
+			} else {
+				assert.IsType(t, new(plugin.RestrictionError), err)
+			}
+		})
+	}
+}
+
+func TestMembershipAge(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		ctx    *plugin.Context
+		expect error
+	}{
+		{
+			name: "not a guild",
+			ctx: &plugin.Context{
+				Localizer: i18n.NewFallbackLocalizer(),
+				InvokedCommand: mock.ResolveCommand(plugin.BuiltInSource, mock.Command{
+					ChannelTypes: plugin.AllChannels,
+				}),
+			},
+			expect: NewFatalChannelTypesError(i18n.NewFallbackLocalizer(), plugin.GuildChannels),
+		},
+		{
+			name: "old enough",
+			ctx: &plugin.Context{
+				Message: discord.Message{GuildID: 123},
+				Member: &discord.Member{
+					Joined: discord.NewTimestamp(time.Now().Add(-48 * time.Hour)),
+				},
+				InvokedCommand: mock.ResolveCommand(plugin.BuiltInSource, mock.Command{
+					ChannelTypes: plugin.AllChannels,
+				}),
+			},
+			expect: nil,
+		},
+		{
+			name: "too young",
+			ctx: &plugin.Context{
+				Message: discord.Message{GuildID: 123},
+				Member: &discord.Member{
+					Joined: discord.NewTimestamp(time.Now()),
+				},
+				InvokedCommand: mock.ResolveCommand(plugin.BuiltInSource, mock.Command{
+					ChannelTypes: plugin.AllChannels,
+				}),
+			},
+			expect: plugin.NewRestrictionErrorl(membershipAgeError.
+				WithPlaceholders(&ageErrorPlaceholders{
+					MinAge:    "1d",
+					Remaining: "1d",
+				})),
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := MembershipAge(24*time.Hour)(nil, c.ctx)
+			assert.Equal(t, c.expect, actual)
+		})
+	}
+}
@@ -0,0 +1,354 @@
+package restriction
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/mavolin/disstate/v4/pkg/state"
+
+	"github.com/mavolin/adam/internal/fileutil"
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+// Rules are the allow and deny lists of a plugin in a guild.
+//
+// Deny lists take precedence over allow lists.
+// If multiple allow lists are set, the invoke must satisfy all of them.
+// Empty lists are ignored.
+type Rules struct {
+	// AllowedUsers are the users that may use the plugin.
+	AllowedUsers []discord.UserID `json:"allowed_users,omitempty"`
+	// DeniedUsers are the users that may not use the plugin.
+	DeniedUsers []discord.UserID `json:"denied_users,omitempty"`
+	// AllowedRoles are the roles of which the invoking user must have at
+	// least one.
+	AllowedRoles []discord.RoleID `json:"allowed_roles,omitempty"`
+	// DeniedRoles are the roles of which the invoking user must have none.
+	DeniedRoles []discord.RoleID `json:"denied_roles,omitempty"`
+	// AllowedChannels are the channels in which the plugin may be used.
+	AllowedChannels []discord.ChannelID `json:"allowed_channels,omitempty"`
+	// DeniedChannels are the channels in which the plugin may not be used.
+	DeniedChannels []discord.ChannelID `json:"denied_channels,omitempty"`
+}
+
+// IsEmpty checks if none of the lists of the Rules are set.
+func (r Rules) IsEmpty() bool {
+	return len(r.AllowedUsers) == 0 && len(r.DeniedUsers) == 0 &&
+		len(r.AllowedRoles) == 0 && len(r.DeniedRoles) == 0 &&
+		len(r.AllowedChannels) == 0 && len(r.DeniedChannels) == 0
+}
+
+// RestrictionFunc returns the plugin.RestrictionFunc enforcing the Rules.
+// If the Rules are empty, RestrictionFunc returns nil.
+func (r Rules) RestrictionFunc() plugin.RestrictionFunc {
+	if r.IsEmpty() {
+		return nil
+	}
+
+	return func(s *state.State, ctx *plugin.Context) error {
+		for _, id := range r.DeniedUsers {
+			if id == ctx.Author.ID {
+				return plugin.DefaultFatalRestrictionError
+			}
+		}
+
+		for _, id := range r.DeniedChannels {
+			if id == ctx.ChannelID {
+				return plugin.NewRestrictionErrorl(deniedChannelError)
+			}
+		}
+
+		if ctx.Member != nil {
+			for _, denied := range r.DeniedRoles {
+				for _, id := range ctx.Member.RoleIDs {
+					if denied == id {
+						return plugin.NewRestrictionErrorl(deniedRoleError)
+					}
+				}
+			}
+		}
+
+		funcs := make([]plugin.RestrictionFunc, 0, 3)
+
+		if len(r.AllowedUsers) > 0 {
+			funcs = append(funcs, Users(r.AllowedUsers...))
+		}
+
+		if len(r.AllowedRoles) > 0 {
+			funcs = append(funcs, MustAnyRole(r.AllowedRoles...))
+		}
+
+		if len(r.AllowedChannels) > 0 {
+			funcs = append(funcs, Channels(r.AllowedChannels...))
+		}
+
+		switch len(funcs) {
+		case 0:
+			return nil
+		case 1:
+			return funcs[0](s, ctx)
+		default:
+			return All(funcs...)(s, ctx)
+		}
+	}
+}
+
+// guildRules are the Rules of the plugins of multiple guilds.
+type guildRules map[discord.GuildID]map[plugin.ID]Rules
+
+// get returns the Rules of the plugin with the passed id in the guild with
+// the passed id.
+func (r guildRules) get(guildID discord.GuildID, pluginID plugin.ID) Rules {
+	return r[guildID][pluginID]
+}
+
+// set sets the Rules of the plugin with the passed id in the guild with the
+// passed id.
+// If the passed Rules are empty, they are removed instead.
+func (r guildRules) set(guildID discord.GuildID, pluginID plugin.ID, rules Rules) {
+	if rules.IsEmpty() {
+		delete(r[guildID], pluginID)
+
+		if len(r[guildID]) == 0 {
+			delete(r, guildID)
+		}
+
+		return
+	}
+
+	if r[guildID] == nil {
+		r[guildID] = make(map[plugin.ID]Rules)
+	}
+
+	r[guildID][pluginID] = rules
+}
+
+// =============================================================================
+// MemoryProvider
+// =====================================================================================
+
+// MemoryProvider is a plugin.RestrictionProvider that keeps the Rules of all
+// guilds in memory.
+type MemoryProvider struct {
+	rules guildRules
+	mutex sync.RWMutex
+}
+
+var _ plugin.RestrictionProvider = new(MemoryProvider)
+
+// NewMemoryProvider creates a new *MemoryProvider without any Rules.
+func NewMemoryProvider() *MemoryProvider {
+	return &MemoryProvider{rules: make(guildRules)}
+}
+
+func (p *MemoryProvider) Restrictions(guildID discord.GuildID, pluginID plugin.ID) (plugin.RestrictionFunc, error) {
+	rules, err := p.Rules(guildID, pluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rules.RestrictionFunc(), nil
+}
+
+// Rules returns the Rules of the plugin with the passed id in the guild with
+// the passed id.
+func (p *MemoryProvider) Rules(guildID discord.GuildID, pluginID plugin.ID) (Rules, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.rules.get(guildID, pluginID), nil
+}
+
+// SetRules sets the Rules of the plugin with the passed id in the guild with
+// the passed id.
+// Empty Rules remove all restrictions of the plugin.
+func (p *MemoryProvider) SetRules(guildID discord.GuildID, pluginID plugin.ID, rules Rules) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.rules.set(guildID, pluginID, rules)
+	return nil
+}
+
+// =============================================================================
+// FileProvider
+// =====================================================================================
+
+// FileProvider is a plugin.RestrictionProvider that persists the Rules of
+// all guilds in a JSON file.
+//
+// The file is only read again, if it was modified since it was last read.
+// Therefore, it may also be edited by hand or by another process while the
+// bot is running.
+//
+// To synchronize SetRules between multiple processes using the same file, a
+// lock file with the same path as the file, suffixed with '.lock', is
+// created while the Rules are updated.
+// Lock files older than LockTimeout are considered stale and will be
+// removed.
+// If the lock can't be acquired within AcquireTimeout, SetRules fails with
+// ErrLockTimeout.
+type FileProvider struct {
+	// LockTimeout is the time after which a lock file is considered stale.
+	//
+	// Default: 10 * time.Second
+	LockTimeout time.Duration
+	// AcquireTimeout is the maximum time SetRules waits for the lock file,
+	// before failing with ErrLockTimeout.
+	//
+	// Default: 20 * time.Second
+	AcquireTimeout time.Duration
+
+	path string
+
+	// rules are the Rules read from the file.
+	rules guildRules
+	// modTime is the modification time of the file when it was last read.
+	modTime time.Time
+	mutex   sync.Mutex
+}
+
+var _ plugin.RestrictionProvider = new(FileProvider)
+
+// ErrLockTimeout is the error returned by FileProvider.SetRules, if the lock
+// file could not be acquired within the provider's AcquireTimeout.
+var ErrLockTimeout = errors.New("restriction: timed out waiting for the lock of the file provider")
+
+// NewFileProvider creates a new *FileProvider that stores its Rules in the
+// file at the passed path.
+// If the file does not exist, it will be created, once Rules are set.
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{
+		LockTimeout:    10 * time.Second,
+		AcquireTimeout: 20 * time.Second,
+		path:           path,
+	}
+}
+
+func (p *FileProvider) Restrictions(guildID discord.GuildID, pluginID plugin.ID) (plugin.RestrictionFunc, error) {
+	rules, err := p.Rules(guildID, pluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rules.RestrictionFunc(), nil
+}
+
+// Rules returns the Rules of the plugin with the passed id in the guild with
+// the passed id.
+func (p *FileProvider) Rules(guildID discord.GuildID, pluginID plugin.ID) (Rules, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if err := p.load(); err != nil {
+		return Rules{}, err
+	}
+
+	return p.rules.get(guildID, pluginID), nil
+}
+
+// SetRules sets the Rules of the plugin with the passed id in the guild with
+// the passed id, and writes them to the file.
+// Empty Rules remove all restrictions of the plugin.
+func (p *FileProvider) SetRules(guildID discord.GuildID, pluginID plugin.ID, rules Rules) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	unlock, err := p.lock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	// another process may have modified the file within the precision of the
+	// modification time, so always read it again
+	p.rules = nil
+
+	if err = p.load(); err != nil {
+		return err
+	}
+
+	p.rules.set(guildID, pluginID, rules)
+
+	return p.write()
+}
+
+// load reads the Rules from the provider's file, if the file was modified
+// since it was last read.
+//
+// p.mutex must be locked.
+func (p *FileProvider) load() error {
+	info, err := os.Stat(p.path)
+	if os.IsNotExist(err) {
+		if p.rules == nil {
+			p.rules = make(guildRules)
+		}
+
+		return nil
+	} else if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if p.rules != nil && info.ModTime().Equal(p.modTime) {
+		return nil
+	}
+
+	data, err := fileutil.ReadFile(p.path)
+	if err != nil {
+		return err
+	}
+
+	rules := make(guildRules)
+
+	if len(data) > 0 {
+		if err = json.Unmarshal(data, &rules); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	p.rules = rules
+	p.modTime = info.ModTime()
+
+	return nil
+}
+
+// lock acquires the lock file of the provider.
+// It returns a function that releases the lock.
+func (p *FileProvider) lock() (func(), error) {
+	acquireTimeout := p.AcquireTimeout
+	if acquireTimeout <= 0 {
+		acquireTimeout = 20 * time.Second
+	}
+
+	unlock, err := fileutil.Lock(p.path, p.LockTimeout, acquireTimeout)
+	if errors.Is(err, fileutil.ErrLockTimeout) {
+		return nil, ErrLockTimeout
+	}
+
+	return unlock, err
+}
+
+// write atomically writes the Rules to the provider's file.
+//
+// p.mutex must be locked.
+func (p *FileProvider) write() error {
+	data, err := json.Marshal(p.rules)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err = fileutil.WriteFile(p.path, data); err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(p.path); err == nil {
+		p.modTime = info.ModTime()
+	}
+
+	return nil
+}
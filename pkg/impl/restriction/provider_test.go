@@ -0,0 +1,208 @@
+package restriction
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+func TestRules_RestrictionFunc(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, Rules{}.RestrictionFunc())
+	})
+
+	testCases := []struct {
+		name   string
+		rules  Rules
+		ctx    *plugin.Context
+		expect error
+	}{
+		{
+			name:  "denied user",
+			rules: Rules{DeniedUsers: []discord.UserID{123}},
+			ctx: &plugin.Context{
+				Message: discord.Message{Author: discord.User{ID: 123}},
+			},
+			expect: plugin.DefaultFatalRestrictionError,
+		},
+		{
+			name:  "denied channel",
+			rules: Rules{DeniedChannels: []discord.ChannelID{456}},
+			ctx: &plugin.Context{
+				Message: discord.Message{ChannelID: 456},
+			},
+			expect: plugin.NewRestrictionErrorl(deniedChannelError),
+		},
+		{
+			name:  "denied role",
+			rules: Rules{DeniedRoles: []discord.RoleID{789}},
+			ctx: &plugin.Context{
+				Message: discord.Message{GuildID: 1},
+				Member:  &discord.Member{RoleIDs: []discord.RoleID{321, 789}},
+			},
+			expect: plugin.NewRestrictionErrorl(deniedRoleError),
+		},
+		{
+			name:  "denied role direct message",
+			rules: Rules{DeniedRoles: []discord.RoleID{789}},
+			ctx:   new(plugin.Context),
+		},
+		{
+			name:  "allowed user",
+			rules: Rules{AllowedUsers: []discord.UserID{123}},
+			ctx: &plugin.Context{
+				Message: discord.Message{Author: discord.User{ID: 123}},
+			},
+		},
+		{
+			name:  "not allowed user",
+			rules: Rules{AllowedUsers: []discord.UserID{123}},
+			ctx: &plugin.Context{
+				Message: discord.Message{Author: discord.User{ID: 456}},
+			},
+			expect: plugin.DefaultFatalRestrictionError,
+		},
+		{
+			name: "deny before allow",
+			rules: Rules{
+				AllowedUsers:   []discord.UserID{123},
+				DeniedChannels: []discord.ChannelID{456},
+			},
+			ctx: &plugin.Context{
+				Message: discord.Message{
+					ChannelID: 456,
+					Author:    discord.User{ID: 123},
+				},
+			},
+			expect: plugin.NewRestrictionErrorl(deniedChannelError),
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := c.rules.RestrictionFunc()(nil, c.ctx)
+			assert.Equal(t, c.expect, actual)
+		})
+	}
+}
+
+func TestMemoryProvider(t *testing.T) {
+	t.Parallel()
+
+	p := NewMemoryProvider()
+
+	f, err := p.Restrictions(123, ".mod")
+	require.NoError(t, err)
+	assert.Nil(t, f)
+
+	rules := Rules{DeniedUsers: []discord.UserID{456}}
+	require.NoError(t, p.SetRules(123, ".mod", rules))
+
+	actual, err := p.Rules(123, ".mod")
+	require.NoError(t, err)
+	assert.Equal(t, rules, actual)
+
+	f, err = p.Restrictions(123, ".mod")
+	require.NoError(t, err)
+	assert.NotNil(t, f)
+
+	// other guild
+	f, err = p.Restrictions(789, ".mod")
+	require.NoError(t, err)
+	assert.Nil(t, f)
+
+	require.NoError(t, p.SetRules(123, ".mod", Rules{}))
+	assert.Empty(t, p.rules)
+}
+
+func TestFileProvider(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "restrictions.json")
+
+	p := NewFileProvider(path)
+
+	f, err := p.Restrictions(123, ".mod.cmd")
+	require.NoError(t, err)
+	assert.Nil(t, f)
+
+	rules := Rules{
+		AllowedRoles:    []discord.RoleID{456},
+		AllowedChannels: []discord.ChannelID{789},
+	}
+	require.NoError(t, p.SetRules(123, ".mod.cmd", rules))
+
+	// simulate a restart
+	p = NewFileProvider(path)
+
+	actual, err := p.Rules(123, ".mod.cmd")
+	require.NoError(t, err)
+	assert.Equal(t, rules, actual)
+
+	f, err = p.Restrictions(123, ".mod.cmd")
+	require.NoError(t, err)
+	assert.NotNil(t, f)
+}
+
+func TestFileProvider_SetRules(t *testing.T) {
+	t.Parallel()
+
+	t.Run("lock timeout", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "restrictions.json")
+
+		p := NewFileProvider(path)
+		p.AcquireTimeout = 50 * time.Millisecond
+
+		require.NoError(t, ioutil.WriteFile(path+".lock", nil, 0o600))
+
+		err := p.SetRules(123, ".mod.cmd", Rules{AllowedRoles: []discord.RoleID{456}})
+		assert.Equal(t, ErrLockTimeout, err)
+	})
+
+	t.Run("external change", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "restrictions.json")
+
+		p1 := NewFileProvider(path)
+		p2 := NewFileProvider(path)
+
+		rules1 := Rules{AllowedRoles: []discord.RoleID{456}}
+		rules2 := Rules{DeniedUsers: []discord.UserID{789}}
+
+		// load the empty file into p2
+		_, err := p2.Rules(123, ".mod")
+		require.NoError(t, err)
+
+		require.NoError(t, p1.SetRules(123, ".mod", rules1))
+		require.NoError(t, p2.SetRules(123, ".cmd", rules2))
+
+		p := NewFileProvider(path)
+
+		actual, err := p.Rules(123, ".mod")
+		require.NoError(t, err)
+		assert.Equal(t, rules1, actual)
+
+		actual, err = p.Rules(123, ".cmd")
+		require.NoError(t, err)
+		assert.Equal(t, rules2, actual)
+
+		assert.NoFileExists(t, path+".lock")
+	})
+}
@@ -0,0 +1,198 @@
+package restriction
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mavolin/disstate/v4/pkg/state"
+
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+type (
+	// WeeklyWindow is a time window that recurs every week.
+	WeeklyWindow struct {
+		// Weekday is the day of the week on which the window starts.
+		Weekday time.Weekday
+		// Start is the wall clock time on Weekday at which the window starts,
+		// e.g. 18*time.Hour for 6 p.m.
+		Start time.Duration
+		// Duration is the length of the window in wall clock time, i.e. a
+		// window starting at 6 p.m. with a Duration of 4 hours always ends
+		// at 10 p.m., even if the clocks are changed in between.
+		// A window may span into the following days, but must not be longer
+		// than a week.
+		Duration time.Duration
+	}
+
+	// DateRange is a range of time, starting at Start and ending before End.
+	DateRange struct {
+		Start time.Time
+		End   time.Time
+	}
+)
+
+// Weekly asserts that a command is invoked during one of the passed weekly
+// windows.
+// The windows are evaluated in the passed *time.Location.
+//
+// If no windows are passed, the command is always allowed.
+func Weekly(loc *time.Location, windows ...WeeklyWindow) plugin.RestrictionFunc {
+	return func(*state.State, *plugin.Context) error {
+		if len(windows) == 0 {
+			return nil
+		}
+
+		open, next := weeklyAvailability(time.Now().In(loc), loc, windows)
+		if open {
+			return nil
+		}
+
+		return newScheduleError(next)
+	}
+}
+
+// Weekdays asserts that a command is invoked on one of the passed days of the
+// week, evaluated in the passed *time.Location.
+//
+// For example, Weekdays(loc, time.Saturday, time.Sunday) only allows a
+// command on weekends.
+//
+// If no days are passed, the command is always allowed.
+func Weekdays(loc *time.Location, days ...time.Weekday) plugin.RestrictionFunc {
+	return func(*state.State, *plugin.Context) error {
+		if len(days) == 0 {
+			return nil
+		}
+
+		open, next := weekdayAvailability(time.Now().In(loc), loc, days)
+		if open {
+			return nil
+		}
+
+		return newScheduleError(next)
+	}
+}
+
+// DateRanges asserts that a command is invoked during one of the passed
+// DateRanges.
+// If all ranges have ended, a fatal *plugin.RestrictionError is returned.
+//
+// If no ranges are passed, the command is always allowed.
+func DateRanges(ranges ...DateRange) plugin.RestrictionFunc {
+	return func(*state.State, *plugin.Context) error {
+		if len(ranges) == 0 {
+			return nil
+		}
+
+		open, next := dateRangeAvailability(time.Now(), ranges)
+		if open {
+			return nil
+		}
+
+		if next.IsZero() {
+			return plugin.NewFatalRestrictionErrorl(scheduleExpiredError)
+		}
+
+		return newScheduleError(next)
+	}
+}
+
+// weeklyAvailability checks if t lies within one of the passed windows.
+// If not, it returns the time at which the next window starts.
+func weeklyAvailability(
+	t time.Time, loc *time.Location, windows []WeeklyWindow,
+) (open bool, next time.Time) {
+	year, month, day := t.Date()
+	// the day of the month of the sunday of the current week
+	sunday := day - int(t.Weekday())
+
+	// also check the previous week, as its windows may span into this one,
+	// and the next week, in case all of this week's windows are over
+	for week := -1; week <= 1; week++ {
+		for _, w := range windows {
+			day := sunday + 7*week + int(w.Weekday)
+
+			start := wallClock(year, month, day, w.Start, loc)
+			end := wallClock(year, month, day, w.Start+w.Duration, loc)
+
+			if !t.Before(start) && t.Before(end) {
+				return true, time.Time{}
+			}
+
+			if start.After(t) && (next.IsZero() || start.Before(next)) {
+				next = start
+			}
+		}
+	}
+
+	return false, next
+}
+
+// weekdayAvailability checks if t lies on one of the passed days.
+// If not, it returns the start of the next of those days.
+func weekdayAvailability(t time.Time, loc *time.Location, days []time.Weekday) (open bool, next time.Time) {
+	year, month, day := t.Date()
+
+	for i := 0; i < 7; i++ {
+		weekday := (t.Weekday() + time.Weekday(i)) % 7
+
+		for _, d := range days {
+			if d != weekday {
+				continue
+			}
+
+			if i == 0 {
+				return true, time.Time{}
+			}
+
+			return false, time.Date(year, month, day+i, 0, 0, 0, 0, loc)
+		}
+	}
+
+	return false, time.Time{}
+}
+
+// wallClock returns the time at which the clocks in loc show the passed
+// offset from the start of the passed day.
+// Unlike adding the offset to the start of the day, this isn't affected by
+// clock changes, such as daylight saving time.
+func wallClock(year int, month time.Month, day int, offset time.Duration, loc *time.Location) time.Time {
+	days := int(offset / (24 * time.Hour))
+	offset %= 24 * time.Hour
+
+	return time.Date(year, month, day+days,
+		int(offset/time.Hour), int(offset%time.Hour/time.Minute), int(offset%time.Minute/time.Second),
+		int(offset%time.Second), loc)
+}
+
+// dateRangeAvailability checks if t lies within one of the passed ranges.
+// If not, it returns the time at which the next range starts, or the zero
+// time, if all ranges have ended.
+func dateRangeAvailability(t time.Time, ranges []DateRange) (open bool, next time.Time) {
+	for _, r := range ranges {
+		if !t.Before(r.Start) && t.Before(r.End) {
+			return true, time.Time{}
+		}
+
+		if r.Start.After(t) && (next.IsZero() || r.Start.Before(next)) {
+			next = r.Start
+		}
+	}
+
+	return false, next
+}
+
+// newScheduleError creates a new *plugin.RestrictionError stating that the
+// command will be available again at the passed time.
+// The time is sent as a Discord timestamp, so that it is displayed in the
+// timezone and locale of the user.
+func newScheduleError(next time.Time) *plugin.RestrictionError {
+	unix := next.Unix()
+
+	return plugin.NewRestrictionErrorl(scheduleUnavailableError.
+		WithPlaceholders(&scheduleUnavailableErrorPlaceholders{
+			Time:         fmt.Sprintf("<t:%d:f>", unix),
+			RelativeTime: fmt.Sprintf("<t:%d:R>", unix),
+		}))
+}
@@ -0,0 +1,233 @@
+package restriction
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // for the daylight saving time tests
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+func TestDateRanges(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no ranges", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, DateRanges()(nil, new(plugin.Context)))
+	})
+
+	t.Run("upcoming", func(t *testing.T) {
+		t.Parallel()
+
+		start := time.Now().Add(time.Hour)
+
+		f := DateRanges(DateRange{Start: start, End: start.Add(time.Hour)})
+
+		assert.Equal(t, newScheduleError(start), f(nil, new(plugin.Context)))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		t.Parallel()
+
+		end := time.Now().Add(-time.Hour)
+
+		f := DateRanges(DateRange{Start: end.Add(-time.Hour), End: end})
+
+		expect := plugin.NewFatalRestrictionErrorl(scheduleExpiredError)
+		assert.Equal(t, expect, f(nil, new(plugin.Context)))
+	})
+}
+
+func Test_weeklyAvailability(t *testing.T) {
+	t.Parallel()
+
+	loc := time.FixedZone("UTC+2", 2*60*60)
+
+	// 2021-06-02 is a wednesday
+	wednesday := func(hour int) time.Time {
+		return time.Date(2021, 6, 2, hour, 0, 0, 0, loc)
+	}
+
+	testCases := []struct {
+		name    string
+		t       time.Time
+		windows []WeeklyWindow
+		open    bool
+		next    time.Time
+	}{
+		{
+			name: "open",
+			t:    wednesday(12),
+			windows: []WeeklyWindow{
+				{Weekday: time.Wednesday, Start: 10 * time.Hour, Duration: 4 * time.Hour},
+			},
+			open: true,
+		},
+		{
+			name: "later same day",
+			t:    wednesday(8),
+			windows: []WeeklyWindow{
+				{Weekday: time.Wednesday, Start: 10 * time.Hour, Duration: 4 * time.Hour},
+			},
+			next: wednesday(10),
+		},
+		{
+			name: "next week",
+			t:    wednesday(16),
+			windows: []WeeklyWindow{
+				{Weekday: time.Wednesday, Start: 10 * time.Hour, Duration: 4 * time.Hour},
+			},
+			next: time.Date(2021, 6, 9, 10, 0, 0, 0, loc),
+		},
+		{
+			name: "spans from previous week",
+			t:    time.Date(2021, 6, 6, 1, 0, 0, 0, loc), // sunday
+			windows: []WeeklyWindow{
+				{Weekday: time.Saturday, Start: 20 * time.Hour, Duration: 8 * time.Hour},
+			},
+			open: true,
+		},
+		{
+			name: "earliest window",
+			t:    wednesday(16),
+			windows: []WeeklyWindow{
+				{Weekday: time.Saturday, Duration: 24 * time.Hour},
+				{Weekday: time.Friday, Start: 18 * time.Hour, Duration: time.Hour},
+			},
+			next: time.Date(2021, 6, 4, 18, 0, 0, 0, loc),
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			open, next := weeklyAvailability(c.t, loc, c.windows)
+			assert.Equal(t, c.open, open)
+			assert.True(t, c.next.Equal(next), "expected %s, but got %s", c.next, next)
+		})
+	}
+
+	t.Run("daylight saving time", func(t *testing.T) {
+		t.Parallel()
+
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+
+		// on 2021-03-28, a sunday, the clocks in berlin were set forward from
+		// 2 a.m. to 3 a.m.
+		windows := []WeeklyWindow{{Weekday: time.Sunday, Start: 18 * time.Hour, Duration: 2 * time.Hour}}
+
+		open, next := weeklyAvailability(time.Date(2021, 3, 28, 17, 30, 0, 0, berlin), berlin, windows)
+		assert.False(t, open)
+
+		expect := time.Date(2021, 3, 28, 18, 0, 0, 0, berlin)
+		assert.True(t, expect.Equal(next), "expected %s, but got %s", expect, next)
+
+		open, _ = weeklyAvailability(time.Date(2021, 3, 28, 19, 30, 0, 0, berlin), berlin, windows)
+		assert.True(t, open)
+
+		open, _ = weeklyAvailability(time.Date(2021, 3, 28, 20, 30, 0, 0, berlin), berlin, windows)
+		assert.False(t, open)
+	})
+}
+
+func Test_weekdayAvailability(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		t    time.Time
+		days []time.Weekday
+		open bool
+		next time.Time
+	}{
+		{
+			name: "open",
+			t:    time.Date(2021, 6, 2, 12, 0, 0, 0, berlin), // wednesday
+			days: []time.Weekday{time.Wednesday},
+			open: true,
+		},
+		{
+			name: "next",
+			t:    time.Date(2021, 6, 2, 12, 0, 0, 0, berlin),
+			days: []time.Weekday{time.Monday, time.Friday},
+			next: time.Date(2021, 6, 4, 0, 0, 0, 0, berlin),
+		},
+		{
+			name: "daylight saving time",
+			// on 2021-10-31, a sunday, the clocks in berlin were set back,
+			// making the day 25 hours long
+			t:    time.Date(2021, 10, 31, 23, 30, 0, 0, berlin),
+			days: []time.Weekday{time.Sunday},
+			open: true,
+		},
+		{
+			name: "after daylight saving time",
+			t:    time.Date(2021, 11, 1, 0, 30, 0, 0, berlin),
+			days: []time.Weekday{time.Sunday},
+			next: time.Date(2021, 11, 7, 0, 0, 0, 0, berlin),
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			open, next := weekdayAvailability(c.t, berlin, c.days)
+			assert.Equal(t, c.open, open)
+			assert.True(t, c.next.Equal(next), "expected %s, but got %s", c.next, next)
+		})
+	}
+}
+
+func Test_dateRangeAvailability(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2021, 6, 2, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name   string
+		ranges []DateRange
+		open   bool
+		next   time.Time
+	}{
+		{
+			name:   "open",
+			ranges: []DateRange{{Start: at.Add(-time.Hour), End: at.Add(time.Hour)}},
+			open:   true,
+		},
+		{
+			name:   "end exclusive",
+			ranges: []DateRange{{Start: at.Add(-time.Hour), End: at}},
+		},
+		{
+			name: "next",
+			ranges: []DateRange{
+				{Start: at.Add(48 * time.Hour), End: at.Add(72 * time.Hour)},
+				{Start: at.Add(24 * time.Hour), End: at.Add(25 * time.Hour)},
+			},
+			next: at.Add(24 * time.Hour),
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			open, next := dateRangeAvailability(at, c.ranges)
+			assert.Equal(t, c.open, open)
+			assert.True(t, c.next.Equal(next), "expected %s, but got %s", c.next, next)
+		})
+	}
+}
@@ -97,3 +97,49 @@ var (
 type userPermissionsDescSinglePlaceholders struct {
 	MissingPermission string
 }
+
+// ================================ Schedule ================================
+
+var (
+	scheduleUnavailableError = i18n.NewFallbackConfig(
+		"restriction.schedule.error.unavailable",
+		"This command is currently unavailable. "+
+			"It will be available again on {{.time}} ({{.relative_time}}).")
+	scheduleExpiredError = i18n.NewFallbackConfig(
+		"restriction.schedule.error.expired",
+		"This command is no longer available.")
+)
+
+type scheduleUnavailableErrorPlaceholders struct {
+	Time         string
+	RelativeTime string
+}
+
+// ================================ Age ================================
+
+var (
+	accountAgeError = i18n.NewFallbackConfig(
+		"restriction.account_age.error.too_young",
+		"Your account must be at least {{.min_age}} old to use this command. "+
+			"Try again in {{.remaining}}.")
+	membershipAgeError = i18n.NewFallbackConfig(
+		"restriction.membership_age.error.too_young",
+		"You must be a member of this server for at least {{.min_age}} to use this command. "+
+			"Try again in {{.remaining}}.")
+)
+
+type ageErrorPlaceholders struct {
+	MinAge    string
+	Remaining string
+}
+
+// ================================ Rules ================================
+
+var (
+	deniedChannelError = i18n.NewFallbackConfig(
+		"restriction.rules.error.denied_channel",
+		"You can't use this command in this channel.")
+	deniedRoleError = i18n.NewFallbackConfig(
+		"restriction.rules.error.denied_role",
+		"One of your roles prevents you from using this command.")
+)
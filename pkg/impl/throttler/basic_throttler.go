@@ -1,80 +1,126 @@
 package throttler
 
 import (
-	"sort"
-	"sync"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/diamondburned/arikawa/v3/discord"
+
+	"github.com/mavolin/adam/pkg/errors"
 )
 
+// Limiter is the algorithm used by the throttlers returned by PerUser,
+// PerMember, PerChannel, and PerGuild, and their variants, to decide whether
+// an entity may invoke a command.
+//
+// Entities are identified by keys, as described in the documentation of
+// Store.
+//
+// All methods of a Limiter must be safe for concurrent use.
+type Limiter interface {
+	// Check checks if the entity with the passed key should be throttled.
+	// If so, Check returns a nil cancelFunc and the duration until the
+	// entity may invoke again.
+	// Otherwise, Check counts the invoke and returns a cancelFunc that undoes
+	// it.
+	Check(key string) (cancelFunc func(), available time.Duration, err error)
+}
+
 type snowflakeThrottler struct {
-	throttled  map[discord.Snowflake][]time.Time
+	store      Store
 	maxInvokes uint
 	duration   time.Duration
+}
+
+var (
+	_ Limiter          = new(snowflakeThrottler)
+	_ LimiterInspector = new(snowflakeThrottler)
+)
 
-	mutex sync.Mutex
+// SlidingWindow returns a Limiter that allows at maximum the passed number
+// of invokes in any period of the passed duration.
+// The times of all invokes inside the window are stored in the passed
+// Store.
+//
+// This is the Limiter used by PerUser, PerMember, PerChannel, and PerGuild.
+// Unlike TokenBucket and FixedWindow, its memory usage grows with
+// maxInvokes.
+func SlidingWindow(s Store, maxInvokes uint, duration time.Duration) Limiter {
+	return newSnowflakeThrottler(s, maxInvokes, duration)
 }
 
 // newSnowflakeThrottler creates a new throttler with the passed inclusive
-// maxInvokes and the passed expiration duration that works on a per-snowflake
-// basis.
-func newSnowflakeThrottler(maxInvokes uint, duration time.Duration) *snowflakeThrottler {
+// maxInvokes and the passed expiration duration that keeps its invokes in
+// the passed Store.
+func newSnowflakeThrottler(s Store, maxInvokes uint, duration time.Duration) *snowflakeThrottler {
 	return &snowflakeThrottler{
-		throttled:  make(map[discord.Snowflake][]time.Time),
+		store:      s,
 		maxInvokes: maxInvokes,
 		duration:   duration,
 	}
 }
 
-// expire removes all invokes for the passed discord.Snowflake before the
-// passed time.Time.
-//
-// It will not lock the mutex.
-func (t *snowflakeThrottler) expire(s discord.Snowflake, before time.Time) {
-	throttled := t.throttled[s]
-
-	i := sort.Search(len(throttled), func(i int) bool {
-		return !throttled[i].Before(before)
-	})
-
-	t.throttled[s] = throttled[i:]
-}
-
 // now is a function that returns the current time.
 // Made replaceable for testing.
 var now = time.Now
 
-// check checks if the entity with the passed snowflake should be throttled.
-// If so check will return nil and the duration until the command can be
-// invoked again.
-// Otherwise, check will return a cancelFunc and add the invoke.
-func (t *snowflakeThrottler) check(s discord.Snowflake) (func(), time.Duration) {
-	t.mutex.Lock()
-	defer t.mutex.Unlock()
-
+func (t *snowflakeThrottler) Check(key string) (func(), time.Duration, error) {
 	now := now()
-	t.expire(s, now.Add(-t.duration))
 
-	throttled := t.throttled[s]
+	oldest, ok, err := t.store.Take(key, now, now.Add(-t.duration), t.maxInvokes)
+	if err != nil {
+		return nil, 0, errors.WithStack(err)
+	}
 
 	// maxInvokes reached, prevent invoke
-	if len(throttled) >= int(t.maxInvokes) {
-		return nil, t.duration - now.Sub(throttled[0])
+	if !ok {
+		return nil, t.duration - now.Sub(oldest), nil
 	}
 
-	t.throttled[s] = append(throttled, now)
+	return func() { _ = t.store.Release(key, now) }, 0, nil
+}
 
-	return func() {
-		t.mutex.Lock()
-		defer t.mutex.Unlock()
+func (t *snowflakeThrottler) Usage(key string) (*Usage, error) {
+	now := now()
+
+	invokes, err := t.store.Invokes(key, now.Add(-t.duration))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var u Usage
+
+	if used := uint(len(invokes)); used < t.maxInvokes {
+		u.Remaining = t.maxInvokes - used
+	}
+
+	if len(invokes) > 0 {
+		u.ResetIn = invokes[len(invokes)-1].Add(t.duration).Sub(now)
+	}
+
+	return &u, nil
+}
+
+func (t *snowflakeThrottler) Reset(key string) error {
+	return errors.WithStack(t.store.Clear(key))
+}
+
+func (t *snowflakeThrottler) Grant(key string, uses uint) error {
+	return errors.WithStack(t.store.Remove(key, now().Add(-t.duration), uses))
+}
+
+// storeKey generates the key of the entity with the passed scope and
+// snowflakes.
+func storeKey(scope string, ids ...discord.Snowflake) string {
+	var b strings.Builder
+
+	b.WriteString(scope)
+
+	for _, id := range ids {
+		b.WriteRune(':')
+		b.WriteString(strconv.FormatUint(uint64(id), 10))
+	}
 
-		throttled := t.throttled[s]
-		for i, cmp := range throttled {
-			if cmp == now {
-				t.throttled[s] = append(throttled[:i], throttled[i+1:]...)
-				return
-			}
-		}
-	}, 0
+	return b.String()
 }
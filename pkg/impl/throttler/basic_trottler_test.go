@@ -4,8 +4,10 @@ import (
 	"testing"
 	"time"
 
-	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/plugin"
 )
 
 func Test_snowflakeThrottler_expire(t *testing.T) {
@@ -64,71 +66,119 @@ func Test_snowflakeThrottler_expire(t *testing.T) {
 		t.Run(c.name, func(t *testing.T) {
 			t.Parallel()
 
-			var s discord.Snowflake = 123
-
-			st := newSnowflakeThrottler(10, 10)
+			s := storeKey("user", 123)
 
-			st.throttled[s] = c.throttled
+			st := newSnowflakeThrottler(NewMemoryStore(), 10, 10)
 
-			st.expire(s, c.before)
+			for _, at := range c.throttled {
+				_, _, err := st.store.Take(s, at, time.Time{}, st.maxInvokes)
+				require.NoError(t, err)
+			}
 
-			assert.Equal(t, c.expect, st.throttled[s])
+			actual, err := st.store.Invokes(s, c.before)
+			require.NoError(t, err)
+			assert.Equal(t, c.expect, actual)
 		})
 	}
 }
 
 func Test_snowflakeThrottler_check(t *testing.T) {
 	t.Run("blocked", func(t *testing.T) {
-		var s discord.Snowflake = 123
+		s := storeKey("user", 123)
 
 		now = func() time.Time {
 			return time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
 		}
 
-		st := newSnowflakeThrottler(2, 30*time.Second)
-		st.throttled[s] = []time.Time{
+		st := newSnowflakeThrottler(NewMemoryStore(), 2, 30*time.Second)
+		checkAt(t, st, s,
 			time.Date(2020, 1, 1, 11, 59, 40, 0, time.UTC),
 			time.Date(2020, 1, 1, 11, 59, 50, 0, time.UTC),
-		}
+		)
 
-		cancelFunc, actualDuration := st.check(s)
+		cancelFunc, actualDuration, err := st.Check(s)
+		require.NoError(t, err)
 		assert.Nil(t, cancelFunc)
 		assert.Equal(t, 10*time.Second, actualDuration)
 	})
 
 	t.Run("pass", func(t *testing.T) {
-		var s discord.Snowflake = 123
+		s := storeKey("user", 123)
 
 		now = func() time.Time {
 			return time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
 		}
 
-		st := newSnowflakeThrottler(2, 30*time.Second)
-		st.throttled[s] = []time.Time{
+		st := newSnowflakeThrottler(NewMemoryStore(), 2, 30*time.Second)
+		checkAt(t, st, s,
 			time.Date(2020, 1, 1, 11, 59, 29, 0, time.UTC),
 			time.Date(2020, 1, 1, 11, 59, 50, 0, time.UTC),
-		}
+		)
 
-		cancelFunc, _ := st.check(s)
+		cancelFunc, _, err := st.Check(s)
+		require.NoError(t, err)
 		assert.NotNil(t, cancelFunc)
 	})
 
 	t.Run("cancel", func(t *testing.T) {
-		var s discord.Snowflake = 123
+		s := storeKey("user", 123)
 
 		now = func() time.Time {
 			return time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
 		}
 
-		st := newSnowflakeThrottler(2, 30*time.Second)
+		st := newSnowflakeThrottler(NewMemoryStore(), 2, 30*time.Second)
 
-		cancelFunc, _ := st.check(s)
+		cancelFunc, _, err := st.Check(s)
+		require.NoError(t, err)
 		assert.NotNil(t, cancelFunc)
 
-		assert.Len(t, st.throttled[s], 1)
+		invokes, err := st.store.Invokes(s, time.Time{})
+		require.NoError(t, err)
+		assert.Len(t, invokes, 1)
 
 		cancelFunc()
 
-		assert.Len(t, st.throttled[s], 0)
+		invokes, err = st.store.Invokes(s, time.Time{})
+		require.NoError(t, err)
+		assert.Len(t, invokes, 0)
 	})
 }
+
+// checkAt checks the entity with the passed key at each of the passed times,
+// using the passed Limiter.
+// Each check must pass.
+func checkAt(t *testing.T, l Limiter, key string, times ...time.Time) {
+	t.Helper()
+
+	prev := now
+	defer func() { now = prev }()
+
+	for _, at := range times {
+		at := at
+		now = func() time.Time { return at }
+
+		cancelFunc, _, err := l.Check(key)
+		require.NoError(t, err)
+		require.NotNil(t, cancelFunc)
+	}
+}
+
+// invokeAt invokes the passed plugin.Throttler using the passed
+// *plugin.Context at each of the passed times.
+// Each invoke must pass.
+func invokeAt(t *testing.T, throttler plugin.Throttler, ctx *plugin.Context, times ...time.Time) {
+	t.Helper()
+
+	prev := now
+	defer func() { now = prev }()
+
+	for _, at := range times {
+		at := at
+		now = func() time.Time { return at }
+
+		cancelFunc, err := throttler.Check(nil, ctx)
+		require.NoError(t, err)
+		require.NotNil(t, cancelFunc)
+	}
+}
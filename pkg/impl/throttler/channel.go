@@ -1,6 +1,7 @@
 package throttler
 
 import (
+	"io"
 	"time"
 
 	"github.com/diamondburned/arikawa/v3/discord"
@@ -11,19 +12,44 @@ import (
 
 // channel is a plugin.Throttler that works on a per-channel basis.
 type channel struct {
-	throttler *snowflakeThrottler
+	throttler Limiter
+	// closer is the *MemoryStore created by PerChannel, or nil.
+	closer io.Closer
 }
 
-var _ plugin.Throttler = new(channel)
+var (
+	_ Inspector = new(channel)
+	_ io.Closer = new(channel)
+)
 
 // PerChannel returns a new plugin.Throttler that works on a per-channel basis.
 // It allows at maximum the passed number of invokes in the passed duration.
+//
+// The returned plugin.Throttler implements io.Closer, and should be closed,
+// once it is no longer used, to remove its *MemoryStore from the
+// DefaultJanitor.
 func PerChannel(maxInvokes uint, duration time.Duration) plugin.Throttler {
-	return &channel{throttler: newSnowflakeThrottler(maxInvokes, duration)}
+	s := NewMemoryStore()
+	return &channel{throttler: SlidingWindow(s, maxInvokes, duration), closer: s}
+}
+
+// PerChannelWithStore is the same as PerChannel, but keeps its invokes in the
+// passed Store.
+func PerChannelWithStore(s Store, maxInvokes uint, duration time.Duration) plugin.Throttler {
+	return PerChannelWithLimiter(SlidingWindow(s, maxInvokes, duration))
+}
+
+// PerChannelWithLimiter returns a new plugin.Throttler that works on a
+// per-channel basis, using the passed Limiter.
+func PerChannelWithLimiter(l Limiter) plugin.Throttler {
+	return &channel{throttler: l}
 }
 
 func (g *channel) Check(_ *state.State, ctx *plugin.Context) (func(), error) {
-	cancelFunc, available := g.throttler.check(discord.Snowflake(ctx.ChannelID))
+	cancelFunc, available, err := g.throttler.Check(channelKey(ctx.ChannelID))
+	if err != nil {
+		return nil, err
+	}
 
 	if cancelFunc == nil {
 		return nil, genError(available, channelThrottledErrorSecond, channelThrottledErrorMinute)
@@ -31,3 +57,31 @@ func (g *channel) Check(_ *state.State, ctx *plugin.Context) (func(), error) {
 
 	return cancelFunc, nil
 }
+
+func (g *channel) Usage(t Target) (*Usage, error) {
+	return usage(g.throttler, channelKey(t.ChannelID))
+}
+
+func (g *channel) Reset(t Target) error {
+	return reset(g.throttler, channelKey(t.ChannelID))
+}
+
+func (g *channel) Grant(t Target, uses uint) error {
+	return grant(g.throttler, channelKey(t.ChannelID), uses)
+}
+
+// Close closes the *MemoryStore created by PerChannel.
+// If the throttler was created using PerChannelWithStore or PerChannelWithLimiter, Close is
+// a no-op.
+func (g *channel) Close() error {
+	if g.closer == nil {
+		return nil
+	}
+
+	return g.closer.Close()
+}
+
+// channelKey returns the key of the channel with the passed id.
+func channelKey(channelID discord.ChannelID) string {
+	return storeKey("channel", discord.Snowflake(channelID))
+}
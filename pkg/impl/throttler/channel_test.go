@@ -19,10 +19,10 @@ func Test_channel_Check(t *testing.T) {
 		ctx := &plugin.Context{Message: discord.Message{ChannelID: 123}}
 
 		channel := PerChannel(2, 30*time.Second).(*channel)
-		channel.throttler.throttled[discord.Snowflake(ctx.ChannelID)] = []time.Time{
+		invokeAt(t, channel, ctx,
 			time.Date(2020, 1, 1, 11, 59, 40, 0, time.UTC),
 			time.Date(2020, 1, 1, 11, 59, 50, 0, time.UTC),
-		}
+		)
 
 		cancelFunc, err := channel.Check(nil, ctx)
 		assert.Nil(t, cancelFunc)
@@ -37,10 +37,10 @@ func Test_channel_Check(t *testing.T) {
 		ctx := &plugin.Context{Message: discord.Message{Author: discord.User{ID: 123}}}
 
 		channel := PerChannel(2, 30*time.Second).(*channel)
-		channel.throttler.throttled[discord.Snowflake(ctx.Author.ID)] = []time.Time{
+		invokeAt(t, channel, ctx,
 			time.Date(2020, 1, 1, 11, 59, 29, 0, time.UTC),
 			time.Date(2020, 1, 1, 11, 59, 50, 0, time.UTC),
-		}
+		)
 
 		cancelFunc, _ := channel.Check(nil, ctx)
 		assert.NotNil(t, cancelFunc)
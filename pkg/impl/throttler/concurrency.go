@@ -0,0 +1,289 @@
+package throttler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mavolin/disstate/v4/pkg/state"
+
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+// ConcurrencyLimits are the limits of a throttler created by Concurrent.
+type ConcurrencyLimits struct {
+	// User is the maximum number of invokes a single user may have running
+	// at the same time.
+	// If User is 0, the invokes of users are not limited.
+	User uint
+	// Channel is the maximum number of invokes that may run in a single
+	// channel at the same time.
+	// If Channel is 0, the invokes in channels are not limited.
+	Channel uint
+	// Guild is the maximum number of invokes that may run in a single guild
+	// at the same time.
+	// If Guild is 0, the invokes in guilds are not limited.
+	Guild uint
+
+	// QueueSize is the maximum number of invokes that may wait for another
+	// invoke to finish, if a limit is reached.
+	// Each user, channel, and guild has its own queue.
+	// A queued invoke counts towards the queue of every limit that was
+	// reached while it waited, and is throttled if one of those queues is
+	// full.
+	//
+	// If QueueSize is 0, invokes exceeding a limit are throttled
+	// immediately.
+	QueueSize uint
+	// QueueTimeout is the maximum amount of time an invoke waits in the
+	// queue, before it is throttled.
+	// If QueueTimeout is 0, invokes wait until the context.Context of the
+	// invoke is done.
+	QueueTimeout time.Duration
+}
+
+type (
+	concurrent struct {
+		limits ConcurrencyLimits
+
+		// running contains the number of running invokes by key.
+		running map[string]uint
+		// queued contains the number of queued invokes by key.
+		queued map[string]uint
+		// invokes contains the keys of the running invokes.
+		invokes map[*plugin.Context][]string
+		// released is closed and replaced every time an invoke is released, to
+		// notify the queued invokes.
+		released chan struct{}
+
+		mutex sync.Mutex
+	}
+
+	// concurrencyLimit is the limit of a single entity.
+	concurrencyLimit struct {
+		key string
+		max uint
+		// errs are the errors used, if the limit is reached.
+		errs *concurrencyErrors
+	}
+)
+
+var _ plugin.ReleasingThrottler = new(concurrent)
+
+// Concurrent creates a new plugin.Throttler that limits the number of invokes
+// running at the same time, e.g. of commands that do expensive processing.
+// Unlike the other throttlers, an invoke only counts, until the command
+// returns.
+//
+// This requires the throttler to be released after the command returned,
+// which is done by bot.NewThrottlerChecker.
+func Concurrent(limits ConcurrencyLimits) plugin.Throttler {
+	return &concurrent{
+		limits:   limits,
+		running:  make(map[string]uint),
+		queued:   make(map[string]uint),
+		invokes:  make(map[*plugin.Context][]string),
+		released: make(chan struct{}),
+	}
+}
+
+func (t *concurrent) Check(_ *state.State, ctx *plugin.Context) (func(), error) {
+	limits := t.limitsOf(ctx)
+
+	var (
+		timeout <-chan time.Time
+		done    <-chan struct{}
+	)
+
+	if t.limits.QueueTimeout > 0 {
+		timer := time.NewTimer(t.limits.QueueTimeout)
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	if ctx.Ctx != nil {
+		done = ctx.Ctx.Done()
+	}
+
+	t.mutex.Lock()
+
+	// queuedKeys are the keys of the queues the invoke waits in
+	var queuedKeys []string
+
+	for {
+		full := t.full(limits)
+		if len(full) == 0 {
+			break
+		}
+
+		if t.limits.QueueSize == 0 {
+			t.mutex.Unlock()
+			return nil, plugin.NewThrottlingErrorl(full[0].errs.limit)
+		}
+
+		var enqueue []string
+
+		for _, l := range full {
+			if containsKey(queuedKeys, l.key) {
+				continue
+			}
+
+			if t.queued[l.key] >= t.limits.QueueSize {
+				t.dequeue(queuedKeys)
+				t.mutex.Unlock()
+
+				return nil, plugin.NewThrottlingErrorl(l.errs.queueFull)
+			}
+
+			enqueue = append(enqueue, l.key)
+		}
+
+		for _, key := range enqueue {
+			t.queued[key]++
+		}
+
+		queuedKeys = append(queuedKeys, enqueue...)
+
+		released := t.released
+		t.mutex.Unlock()
+
+		select {
+		case <-released:
+		case <-timeout:
+			t.mutex.Lock()
+			t.dequeue(queuedKeys)
+			t.mutex.Unlock()
+
+			return nil, plugin.NewThrottlingErrorl(full[0].errs.limit)
+		case <-done:
+			t.mutex.Lock()
+			t.dequeue(queuedKeys)
+			t.mutex.Unlock()
+
+			return nil, plugin.NewThrottlingErrorl(full[0].errs.limit)
+		}
+
+		t.mutex.Lock()
+	}
+
+	t.dequeue(queuedKeys)
+
+	keys := make([]string, len(limits))
+
+	for i, l := range limits {
+		t.running[l.key]++
+		keys[i] = l.key
+	}
+
+	t.invokes[ctx] = keys
+
+	t.mutex.Unlock()
+
+	return func() { t.Release(ctx) }, nil
+}
+
+func (t *concurrent) Release(ctx *plugin.Context) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	keys, ok := t.invokes[ctx]
+	if !ok {
+		return
+	}
+
+	delete(t.invokes, ctx)
+
+	for _, key := range keys {
+		t.decrement(t.running, key)
+	}
+
+	close(t.released)
+	t.released = make(chan struct{})
+}
+
+// limitsOf returns the limits of the entities of the invoke with the passed
+// context.
+func (t *concurrent) limitsOf(ctx *plugin.Context) []concurrencyLimit {
+	limits := make([]concurrencyLimit, 0, 3)
+
+	if t.limits.User > 0 {
+		limits = append(limits, concurrencyLimit{
+			key:  userKey(ctx.Author.ID),
+			max:  t.limits.User,
+			errs: userConcurrencyErrors,
+		})
+	}
+
+	if t.limits.Channel > 0 {
+		limits = append(limits, concurrencyLimit{
+			key:  channelKey(ctx.ChannelID),
+			max:  t.limits.Channel,
+			errs: channelConcurrencyErrors,
+		})
+	}
+
+	if t.limits.Guild > 0 && ctx.GuildID != 0 {
+		limits = append(limits, concurrencyLimit{
+			key:  guildKey(ctx.GuildID, ctx.Author.ID),
+			max:  t.limits.Guild,
+			errs: guildConcurrencyErrors,
+		})
+	}
+
+	return limits
+}
+
+// full returns the passed limits that are reached.
+//
+// t.mutex must be locked.
+func (t *concurrent) full(limits []concurrencyLimit) []concurrencyLimit {
+	var full []concurrencyLimit
+
+	for _, l := range limits {
+		if t.running[l.key] >= l.max {
+			full = append(full, l)
+		}
+	}
+
+	return full
+}
+
+// dequeue removes an invoke from the queues of the passed keys.
+//
+// t.mutex must be locked.
+func (t *concurrent) dequeue(keys []string) {
+	for _, key := range keys {
+		t.decrement(t.queued, key)
+	}
+}
+
+// decrement decrements the count of the passed key, removing it if it
+// reaches 0.
+//
+// t.mutex must be locked.
+func (t *concurrent) decrement(m map[string]uint, key string) {
+	if m[key] <= 1 {
+		delete(m, key)
+	} else {
+		m[key]--
+	}
+}
+
+// containsKey checks if keys contains the passed key.
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// releaseThrottler calls Release on the passed throttler, if it is a
+// plugin.ReleasingThrottler.
+func releaseThrottler(t plugin.Throttler, ctx *plugin.Context) {
+	if rt, ok := t.(plugin.ReleasingThrottler); ok {
+		rt.Release(ctx)
+	}
+}
@@ -0,0 +1,157 @@
+package throttler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+func Test_concurrent_Check(t *testing.T) {
+	t.Parallel()
+
+	newCtx := func() *plugin.Context {
+		return &plugin.Context{Message: discord.Message{GuildID: 123, Author: discord.User{ID: 456}}}
+	}
+
+	t.Run("limit", func(t *testing.T) {
+		t.Parallel()
+
+		th := Concurrent(ConcurrencyLimits{User: 1}).(*concurrent)
+
+		ctx1 := newCtx()
+
+		_, err := th.Check(nil, ctx1)
+		require.NoError(t, err)
+
+		_, err = th.Check(nil, newCtx())
+		assert.Equal(t, plugin.NewThrottlingErrorl(userConcurrencyErrors.limit), err)
+
+		th.Release(ctx1)
+		// releasing twice must be a no-op
+		th.Release(ctx1)
+
+		_, err = th.Check(nil, newCtx())
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]uint{"user:456": 1}, th.running)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		t.Parallel()
+
+		th := Concurrent(ConcurrencyLimits{Guild: 1})
+
+		cancelFunc, err := th.Check(nil, newCtx())
+		require.NoError(t, err)
+
+		cancelFunc()
+
+		_, err = th.Check(nil, newCtx())
+		assert.NoError(t, err)
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		t.Parallel()
+
+		th := Concurrent(ConcurrencyLimits{User: 1, QueueSize: 1}).(*concurrent)
+
+		ctx1 := newCtx()
+
+		_, err := th.Check(nil, ctx1)
+		require.NoError(t, err)
+
+		queued := make(chan error)
+
+		go func() {
+			_, err := th.Check(nil, newCtx())
+			queued <- err
+		}()
+
+		// wait until the invoke is queued
+		require.Eventually(t, func() bool {
+			th.mutex.Lock()
+			defer th.mutex.Unlock()
+
+			return th.queued["user:456"] == 1
+		}, time.Second, time.Millisecond)
+
+		_, err = th.Check(nil, newCtx())
+		assert.Equal(t, plugin.NewThrottlingErrorl(userConcurrencyErrors.queueFull), err)
+
+		th.Release(ctx1)
+
+		select {
+		case err := <-queued:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			require.Fail(t, "queued invoke was not run")
+		}
+
+		assert.Empty(t, th.queued)
+	})
+
+	t.Run("queue multiple limits", func(t *testing.T) {
+		t.Parallel()
+
+		th := Concurrent(ConcurrencyLimits{User: 1, Channel: 1, QueueSize: 1}).(*concurrent)
+
+		ctx1 := newCtx()
+
+		_, err := th.Check(nil, ctx1)
+		require.NoError(t, err)
+
+		queued := make(chan error)
+
+		go func() {
+			_, err := th.Check(nil, newCtx())
+			queued <- err
+		}()
+
+		// wait until the invoke is queued
+		require.Eventually(t, func() bool {
+			th.mutex.Lock()
+			defer th.mutex.Unlock()
+
+			return th.queued["user:456"] == 1 && th.queued["channel:0"] == 1
+		}, time.Second, time.Millisecond)
+
+		ctx2 := &plugin.Context{Message: discord.Message{GuildID: 123, Author: discord.User{ID: 789}}}
+
+		_, err = th.Check(nil, ctx2)
+		assert.Equal(t, plugin.NewThrottlingErrorl(channelConcurrencyErrors.queueFull), err)
+
+		th.Release(ctx1)
+
+		select {
+		case err := <-queued:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			require.Fail(t, "queued invoke was not run")
+		}
+
+		assert.Empty(t, th.queued)
+	})
+
+	t.Run("queue timeout", func(t *testing.T) {
+		t.Parallel()
+
+		th := Concurrent(ConcurrencyLimits{
+			Channel:      1,
+			QueueSize:    1,
+			QueueTimeout: 10 * time.Millisecond,
+		}).(*concurrent)
+
+		_, err := th.Check(nil, newCtx())
+		require.NoError(t, err)
+
+		_, err = th.Check(nil, newCtx())
+		assert.Equal(t, plugin.NewThrottlingErrorl(channelConcurrencyErrors.limit), err)
+
+		assert.Empty(t, th.queued)
+	})
+}
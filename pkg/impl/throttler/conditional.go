@@ -53,3 +53,13 @@ func (c Conditional) Check(s *state.State, ctx *plugin.Context) (func(), error)
 
 	return func() {}, nil
 }
+
+// Release releases the invoke with the passed context from all throttlers
+// that are plugin.ReleasingThrottlers.
+func (c Conditional) Release(ctx *plugin.Context) {
+	for _, con := range c.Conditions {
+		releaseThrottler(con.Throttler, ctx)
+	}
+
+	releaseThrottler(c.Default, ctx)
+}
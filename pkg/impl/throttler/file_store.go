@@ -0,0 +1,209 @@
+package throttler
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/mavolin/adam/internal/fileutil"
+	"github.com/mavolin/adam/pkg/errors"
+)
+
+// FileStore is a Store that persists all invokes in a JSON file, so that
+// cooldowns survive restarts.
+//
+// A FileStore can be shared between multiple processes, e.g. shards running
+// in separate processes, by using the same file.
+// To synchronize access, a lock file with the same path as the store file,
+// suffixed with '.lock', is created while the store is accessed.
+// Lock files older than LockTimeout are considered stale and will be
+// removed.
+// If the lock can't be acquired within AcquireTimeout, the operation fails
+// with ErrLockTimeout.
+//
+// Expired invokes, and keys that have no invokes left, are removed from the
+// file, whenever an invoke is taken.
+//
+// As every operation reads and rewrites the file, FileStore is not suited
+// for bots with a high volume of invokes.
+type FileStore struct {
+	// LockTimeout is the time after which a lock file is considered stale.
+	//
+	// Default: 10 * time.Second
+	LockTimeout time.Duration
+	// AcquireTimeout is the maximum time an operation waits for the lock
+	// file, before failing with ErrLockTimeout.
+	//
+	// Default: 20 * time.Second
+	AcquireTimeout time.Duration
+
+	path  string
+	mutex sync.Mutex
+}
+
+var _ Store = new(FileStore)
+
+// fileStoreData is the content of the file of a FileStore.
+type fileStoreData struct {
+	Invokes map[string][]time.Time `json:"invokes"`
+	// Windows contains the duration of the window of each key, i.e. the
+	// time between at and expire of the last call to Take.
+	Windows map[string]time.Duration `json:"windows"`
+}
+
+// ErrLockTimeout is the error returned by the methods of a *FileStore, if the
+// lock file could not be acquired within the store's AcquireTimeout.
+var ErrLockTimeout = errors.New("throttler: timed out waiting for the lock of the file store")
+
+// NewFileStore creates a new *FileStore that stores its invokes in the file
+// at the passed path.
+// If the file does not exist, it will be created on first use.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{
+		LockTimeout:    10 * time.Second,
+		AcquireTimeout: 20 * time.Second,
+		path:           path,
+	}
+}
+
+func (s *FileStore) Take(key string, at, exp time.Time, max uint) (oldest time.Time, ok bool, err error) {
+	err = s.update(func(d *fileStoreData) {
+		d.evict(at)
+
+		var invokes []time.Time
+
+		invokes, oldest, ok = take(d.Invokes[key], at, exp, max)
+		d.Windows[key] = at.Sub(exp)
+		d.set(key, invokes)
+	})
+
+	return oldest, ok, err
+}
+
+func (s *FileStore) Release(key string, at time.Time) error {
+	return s.update(func(d *fileStoreData) {
+		d.set(key, release(d.Invokes[key], at))
+	})
+}
+
+func (s *FileStore) Invokes(key string, exp time.Time) ([]time.Time, error) {
+	// the file is replaced atomically when writing, so there is no need to
+	// acquire the lock
+	d, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	return expire(d.Invokes[key], exp), nil
+}
+
+func (s *FileStore) Remove(key string, exp time.Time, n uint) error {
+	return s.update(func(d *fileStoreData) {
+		d.set(key, remove(d.Invokes[key], exp, n))
+	})
+}
+
+func (s *FileStore) Clear(key string) error {
+	return s.update(func(d *fileStoreData) {
+		d.set(key, nil)
+	})
+}
+
+// update locks the store, reads the data from the file, calls f, and
+// writes the data back to the file.
+func (s *FileStore) update(f func(d *fileStoreData)) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	d, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	f(d)
+
+	return s.write(d)
+}
+
+// evict removes all invokes that are expired at the passed time, and the
+// keys that have no invokes left.
+func (d *fileStoreData) evict(now time.Time) {
+	for key, invokes := range d.Invokes {
+		d.set(key, expire(invokes, now.Add(-d.Windows[key])))
+	}
+}
+
+// set stores the passed invokes under the passed key, removing the key if
+// there are none.
+func (d *fileStoreData) set(key string, invokes []time.Time) {
+	setInvokes(d.Invokes, key, invokes)
+
+	if len(invokes) == 0 {
+		delete(d.Windows, key)
+	}
+}
+
+// lock acquires the lock file of the store.
+// It returns a function that releases the lock.
+func (s *FileStore) lock() (func(), error) {
+	acquireTimeout := s.AcquireTimeout
+	if acquireTimeout <= 0 {
+		acquireTimeout = 20 * time.Second
+	}
+
+	unlock, err := fileutil.Lock(s.path, s.LockTimeout, acquireTimeout)
+	if errors.Is(err, fileutil.ErrLockTimeout) {
+		return nil, ErrLockTimeout
+	}
+
+	return unlock, err
+}
+
+// read reads the data from the store's file.
+func (s *FileStore) read() (*fileStoreData, error) {
+	d := &fileStoreData{
+		Invokes: make(map[string][]time.Time),
+		Windows: make(map[string]time.Duration),
+	}
+
+	data, err := fileutil.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return d, nil
+	}
+
+	if err = json.Unmarshal(data, d); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// the maps are nil, if the file contains null values
+	if d.Invokes == nil {
+		d.Invokes = make(map[string][]time.Time)
+	}
+
+	if d.Windows == nil {
+		d.Windows = make(map[string]time.Duration)
+	}
+
+	return d, nil
+}
+
+// write atomically writes the passed data to the store's file.
+func (s *FileStore) write(d *fileStoreData) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return fileutil.WriteFile(s.path, data)
+}
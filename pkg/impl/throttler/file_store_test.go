@@ -0,0 +1,103 @@
+package throttler
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "throttler.json")
+
+	at := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewFileStore(path)
+
+	_, ok, err := s.Take("user:123", at, at.Add(-time.Minute), 1)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// simulate a restart
+	s = NewFileStore(path)
+
+	oldest, ok, err := s.Take("user:123", at.Add(time.Second), at.Add(-time.Minute), 1)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.True(t, at.Equal(oldest))
+
+	require.NoError(t, s.Release("user:123", at))
+
+	_, ok, err = s.Take("user:123", at.Add(time.Second), at.Add(-time.Minute), 1)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	invokes, err := s.Invokes("user:123", at.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, invokes, 1)
+	assert.True(t, at.Add(time.Second).Equal(invokes[0]))
+
+	require.NoError(t, s.Remove("user:123", at.Add(-time.Minute), 1))
+
+	invokes, err = s.Invokes("user:123", at.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, invokes)
+
+	_, _, err = s.Take("user:456", at, at.Add(-time.Minute), 1)
+	require.NoError(t, err)
+	require.NoError(t, s.Clear("user:456"))
+
+	invokes, err = s.Invokes("user:456", at.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, invokes)
+
+	assert.NoFileExists(t, path+".lock")
+}
+
+func TestFileStore_evict(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "throttler.json")
+
+	at := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewFileStore(path)
+
+	_, _, err := s.Take("user:123", at, at.Add(-time.Minute), 1)
+	require.NoError(t, err)
+
+	_, _, err = s.Take("user:456", at, at.Add(-time.Hour), 1)
+	require.NoError(t, err)
+
+	at = at.Add(2 * time.Minute)
+
+	_, _, err = s.Take("user:789", at, at.Add(-time.Minute), 1)
+	require.NoError(t, err)
+
+	d, err := s.read()
+	require.NoError(t, err)
+
+	assert.NotContains(t, d.Invokes, "user:123")
+	assert.NotContains(t, d.Windows, "user:123")
+	assert.Contains(t, d.Invokes, "user:456")
+	assert.Contains(t, d.Invokes, "user:789")
+}
+
+func TestFileStore_lock(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "throttler.json")
+
+	s := NewFileStore(path)
+	s.AcquireTimeout = 50 * time.Millisecond
+
+	require.NoError(t, ioutil.WriteFile(path+".lock", nil, 0o600))
+
+	_, err := s.lock()
+	assert.Equal(t, ErrLockTimeout, err)
+}
@@ -0,0 +1,150 @@
+package throttler
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+type (
+	fixedWindow struct {
+		maxInvokes uint
+		duration   time.Duration
+
+		windows map[string]*window
+		mutex   sync.Mutex
+	}
+
+	window struct {
+		start time.Time
+		count uint
+	}
+)
+
+var (
+	_ Limiter          = new(fixedWindow)
+	_ LimiterInspector = new(fixedWindow)
+	_ Evicter          = new(fixedWindow)
+	_ io.Closer        = new(fixedWindow)
+)
+
+// FixedWindow returns a Limiter that allows at maximum the passed number of
+// invokes per window of the passed duration.
+// Windows are aligned to multiples of the duration since the zero time,
+// e.g. to full minutes, if duration is a minute.
+//
+// In contrast to SlidingWindow, FixedWindow only stores a counter per
+// entity.
+// However, up to twice the number of invokes may be made in a period of the
+// passed duration, if it spans two windows.
+// Its state is kept in memory, and ended windows are evicted by the
+// DefaultJanitor.
+// The returned Limiter implements io.Closer, and should be closed, once it
+// is no longer used, to remove it from the DefaultJanitor.
+func FixedWindow(maxInvokes uint, duration time.Duration) Limiter {
+	w := &fixedWindow{
+		maxInvokes: maxInvokes,
+		duration:   duration,
+		windows:    make(map[string]*window),
+	}
+
+	DefaultJanitor.Add(w)
+
+	return w
+}
+
+func (w *fixedWindow) Check(key string) (func(), time.Duration, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	now := now()
+	start := now.Truncate(w.duration)
+
+	win := w.windows[key]
+	if win == nil || !win.start.Equal(start) {
+		win = &window{start: start}
+		w.windows[key] = win
+	}
+
+	// maxInvokes reached, prevent invoke
+	if win.count >= w.maxInvokes {
+		return nil, start.Add(w.duration).Sub(now), nil
+	}
+
+	win.count++
+
+	return func() {
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+
+		// if the window already ended, win was replaced, and decrementing
+		// its count has no effect
+		if win.count > 0 {
+			win.count--
+		}
+	}, 0, nil
+}
+
+func (w *fixedWindow) Usage(key string) (*Usage, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	now := now()
+	start := now.Truncate(w.duration)
+
+	win := w.windows[key]
+	if win == nil || !win.start.Equal(start) || win.count == 0 {
+		return &Usage{Remaining: w.maxInvokes}, nil
+	}
+
+	u := Usage{ResetIn: start.Add(w.duration).Sub(now)}
+	if win.count < w.maxInvokes {
+		u.Remaining = w.maxInvokes - win.count
+	}
+
+	return &u, nil
+}
+
+func (w *fixedWindow) Reset(key string) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	delete(w.windows, key)
+	return nil
+}
+
+func (w *fixedWindow) Grant(key string, uses uint) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	win := w.windows[key]
+	if win == nil {
+		return nil
+	}
+
+	if uses >= win.count {
+		win.count = 0
+	} else {
+		win.count -= uses
+	}
+
+	return nil
+}
+
+// Evict removes the windows that ended at the passed time.
+func (w *fixedWindow) Evict(now time.Time) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for key, win := range w.windows {
+		if !win.start.Add(w.duration).After(now) {
+			delete(w.windows, key)
+		}
+	}
+}
+
+// Close removes the Limiter from the DefaultJanitor.
+func (w *fixedWindow) Close() error {
+	DefaultJanitor.Remove(w)
+	return nil
+}
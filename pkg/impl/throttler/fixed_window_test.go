@@ -0,0 +1,88 @@
+package throttler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_fixedWindow_Check(t *testing.T) {
+	t.Run("blocked", func(t *testing.T) {
+		now = func() time.Time {
+			return time.Date(2020, 1, 1, 12, 0, 20, 0, time.UTC)
+		}
+
+		w := FixedWindow(2, time.Minute)
+
+		for i := 0; i < 2; i++ {
+			cancelFunc, _, err := w.Check("user:123")
+			require.NoError(t, err)
+			require.NotNil(t, cancelFunc, "invoke %d", i)
+		}
+
+		cancelFunc, available, err := w.Check("user:123")
+		require.NoError(t, err)
+		assert.Nil(t, cancelFunc)
+		assert.Equal(t, 40*time.Second, available)
+	})
+
+	t.Run("next window", func(t *testing.T) {
+		now = func() time.Time {
+			return time.Date(2020, 1, 1, 12, 0, 59, 0, time.UTC)
+		}
+
+		w := FixedWindow(1, time.Minute)
+
+		cancelFunc, _, err := w.Check("user:123")
+		require.NoError(t, err)
+		require.NotNil(t, cancelFunc)
+
+		now = func() time.Time {
+			return time.Date(2020, 1, 1, 12, 1, 0, 0, time.UTC)
+		}
+
+		cancelFunc, _, err = w.Check("user:123")
+		require.NoError(t, err)
+		assert.NotNil(t, cancelFunc)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		now = func() time.Time {
+			return time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+		}
+
+		w := FixedWindow(1, time.Minute)
+
+		cancelFunc, _, err := w.Check("user:123")
+		require.NoError(t, err)
+		require.NotNil(t, cancelFunc)
+
+		cancelFunc()
+
+		cancelFunc, _, err = w.Check("user:123")
+		require.NoError(t, err)
+		assert.NotNil(t, cancelFunc)
+	})
+}
+
+func Test_fixedWindow_Evict(t *testing.T) {
+	now = func() time.Time {
+		return time.Date(2020, 1, 1, 12, 0, 20, 0, time.UTC)
+	}
+
+	w := FixedWindow(2, time.Minute).(*fixedWindow)
+
+	for i := 0; i < 1000; i++ {
+		_, _, err := w.Check(storeKey("user", discord.Snowflake(i)))
+		require.NoError(t, err)
+	}
+
+	w.Evict(time.Date(2020, 1, 1, 12, 0, 59, 0, time.UTC))
+	assert.Len(t, w.windows, 1000)
+
+	w.Evict(time.Date(2020, 1, 1, 12, 1, 0, 0, time.UTC))
+	assert.Empty(t, w.windows)
+}
@@ -0,0 +1,147 @@
+package throttler
+
+import (
+	"sync"
+
+	"github.com/mavolin/disstate/v4/pkg/state"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+// Groups is a registry of named plugin.Throttlers.
+// Commands and modules referencing the same group share a single cooldown,
+// e.g. all image commands may together be invoked 5 times per minute per
+// user.
+type Groups struct {
+	throttlers map[string]plugin.Throttler
+	mutex      sync.RWMutex
+}
+
+// DefaultGroups are the Groups used by Group and RegisterGroup.
+var DefaultGroups = NewGroups()
+
+// NewGroups creates a new *Groups with no groups.
+func NewGroups() *Groups {
+	return &Groups{throttlers: make(map[string]plugin.Throttler)}
+}
+
+// Register registers the passed plugin.Throttler under the passed name,
+// replacing the throttler previously registered under that name.
+func (g *Groups) Register(name string, t plugin.Throttler) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.throttlers[name] = t
+}
+
+// Get returns a plugin.Throttler that uses the throttler registered under
+// the passed name.
+//
+// The throttler is looked up every time the returned plugin.Throttler is
+// checked, so Get may be called before the group is registered, e.g. in the
+// metadata of a command.
+// If no throttler is registered under the name when checking, an internal
+// error is returned.
+func (g *Groups) Get(name string) plugin.Throttler {
+	return &group{groups: g, name: name}
+}
+
+// lookup returns the throttler registered under the passed name.
+func (g *Groups) lookup(name string) (plugin.Throttler, bool) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	t, ok := g.throttlers[name]
+	return t, ok
+}
+
+// Group returns a plugin.Throttler that uses the throttler registered under
+// the passed name in the DefaultGroups.
+// Refer to Groups.Get for more information.
+func Group(name string) plugin.Throttler {
+	return DefaultGroups.Get(name)
+}
+
+// RegisterGroup registers the passed plugin.Throttler under the passed name
+// in the DefaultGroups.
+func RegisterGroup(name string, t plugin.Throttler) {
+	DefaultGroups.Register(name, t)
+}
+
+type group struct {
+	groups *Groups
+	name   string
+}
+
+var (
+	_ Inspector                 = new(group)
+	_ plugin.ReleasingThrottler = new(group)
+)
+
+// throttler returns the throttler of the group.
+func (g *group) throttler() (plugin.Throttler, error) {
+	t, ok := g.groups.lookup(g.name)
+	if !ok {
+		return nil, errors.NewWithStackf("throttler: no throttler registered for group %q", g.name)
+	}
+
+	return t, nil
+}
+
+// inspector returns the throttler of the group as an Inspector.
+func (g *group) inspector() (Inspector, error) {
+	t, err := g.throttler()
+	if err != nil {
+		return nil, err
+	}
+
+	i, ok := t.(Inspector)
+	if !ok {
+		return nil, ErrNotInspectable
+	}
+
+	return i, nil
+}
+
+func (g *group) Check(s *state.State, ctx *plugin.Context) (func(), error) {
+	t, err := g.throttler()
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Check(s, ctx)
+}
+
+func (g *group) Release(ctx *plugin.Context) {
+	if t, ok := g.groups.lookup(g.name); ok {
+		releaseThrottler(t, ctx)
+	}
+}
+
+func (g *group) Usage(t Target) (*Usage, error) {
+	i, err := g.inspector()
+	if err != nil {
+		return nil, err
+	}
+
+	return i.Usage(t)
+}
+
+func (g *group) Reset(t Target) error {
+	i, err := g.inspector()
+	if err != nil {
+		return err
+	}
+
+	return i.Reset(t)
+}
+
+func (g *group) Grant(t Target, uses uint) error {
+	i, err := g.inspector()
+	if err != nil {
+		return err
+	}
+
+	return i.Grant(t, uses)
+}
@@ -0,0 +1,43 @@
+package throttler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+func TestGroups_Get(t *testing.T) {
+	t.Run("shared", func(t *testing.T) {
+		now = func() time.Time {
+			return time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+		}
+
+		g := NewGroups()
+
+		cat := g.Get("images")
+		dog := g.Get("images")
+
+		g.Register("images", PerUser(1, time.Minute))
+
+		ctx := &plugin.Context{Message: discord.Message{Author: discord.User{ID: 123}}}
+
+		cancelFunc, err := cat.Check(nil, ctx)
+		require.NoError(t, err)
+		require.NotNil(t, cancelFunc)
+
+		_, err = dog.Check(nil, ctx)
+		assert.IsType(t, new(plugin.ThrottlingError), err)
+	})
+
+	t.Run("not registered", func(t *testing.T) {
+		g := NewGroups()
+
+		_, err := g.Get("images").Check(nil, new(plugin.Context))
+		assert.Error(t, err)
+	})
+}
@@ -1,6 +1,7 @@
 package throttler
 
 import (
+	"io"
 	"time"
 
 	"github.com/diamondburned/arikawa/v3/discord"
@@ -11,33 +12,88 @@ import (
 
 // guild is a plugin.Throttler that works on a per-guild basis.
 type guild struct {
-	guildThrottler *snowflakeThrottler
-	userThrottler  plugin.Throttler
+	throttler Limiter
+	// closer is the *MemoryStore created by PerGuild, or nil.
+	closer io.Closer
 }
 
-var _ plugin.Throttler = new(guild)
+var (
+	_ Inspector = new(guild)
+	_ io.Closer = new(guild)
+)
 
 // PerGuild returns a new plugin.Throttler that works on a per-guild basis.
 // It allows at maximum the passed number of invokes in the passed duration.
 //
 // All commands invoked in direct messages will be throttled on a per-user
 // basis.
+//
+// The returned plugin.Throttler implements io.Closer, and should be closed,
+// once it is no longer used, to remove its *MemoryStore from the
+// DefaultJanitor.
 func PerGuild(maxInvokes uint, duration time.Duration) plugin.Throttler {
-	return &guild{
-		guildThrottler: newSnowflakeThrottler(maxInvokes, duration),
-		userThrottler:  PerUser(maxInvokes, duration),
-	}
+	s := NewMemoryStore()
+	return &guild{throttler: SlidingWindow(s, maxInvokes, duration), closer: s}
+}
+
+// PerGuildWithStore is the same as PerGuild, but keeps its invokes in the
+// passed Store.
+func PerGuildWithStore(s Store, maxInvokes uint, duration time.Duration) plugin.Throttler {
+	return PerGuildWithLimiter(SlidingWindow(s, maxInvokes, duration))
+}
+
+// PerGuildWithLimiter returns a new plugin.Throttler that works on a
+// per-guild basis, using the passed Limiter.
+func PerGuildWithLimiter(l Limiter) plugin.Throttler {
+	return &guild{throttler: l}
 }
 
 func (g *guild) Check(_ *state.State, ctx *plugin.Context) (func(), error) {
 	if ctx.GuildID == 0 {
-		return g.userThrottler.Check(nil, ctx)
+		return checkUser(g.throttler, ctx)
+	}
+
+	cancelFunc, available, err := g.throttler.Check(guildKey(ctx.GuildID, ctx.Author.ID))
+	if err != nil {
+		return nil, err
 	}
 
-	cancelFunc, available := g.guildThrottler.check(discord.Snowflake(ctx.GuildID))
 	if cancelFunc == nil {
 		return nil, genError(available, guildThrottledErrorSecond, guildThrottledErrorMinute)
 	}
 
 	return cancelFunc, nil
 }
+
+func (g *guild) Usage(t Target) (*Usage, error) {
+	return usage(g.throttler, guildKey(t.GuildID, t.UserID))
+}
+
+func (g *guild) Reset(t Target) error {
+	return reset(g.throttler, guildKey(t.GuildID, t.UserID))
+}
+
+func (g *guild) Grant(t Target, uses uint) error {
+	return grant(g.throttler, guildKey(t.GuildID, t.UserID), uses)
+}
+
+// Close closes the *MemoryStore created by PerGuild.
+// If the throttler was created using PerGuildWithStore or PerGuildWithLimiter, Close is
+// a no-op.
+func (g *guild) Close() error {
+	if g.closer == nil {
+		return nil
+	}
+
+	return g.closer.Close()
+}
+
+// guildKey returns the key of the guild with the passed id.
+// If guildID is 0, the key of the user with the passed id is returned.
+func guildKey(guildID discord.GuildID, userID discord.UserID) string {
+	if guildID == 0 {
+		return userKey(userID)
+	}
+
+	return storeKey("guild", discord.Snowflake(guildID))
+}
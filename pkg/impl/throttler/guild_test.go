@@ -25,10 +25,10 @@ func Test_guild_Check(t *testing.T) {
 			}
 
 			guild := PerGuild(2, 30*time.Second).(*guild)
-			guild.userThrottler.(*user).throttler.throttled[discord.Snowflake(ctx.Author.ID)] = []time.Time{
+			invokeAt(t, guild, ctx,
 				time.Date(2020, 1, 1, 11, 59, 40, 0, time.UTC),
 				time.Date(2020, 1, 1, 11, 59, 50, 0, time.UTC),
-			}
+			)
 
 			cancelFunc, err := guild.Check(nil, ctx)
 			assert.Nil(t, cancelFunc)
@@ -48,10 +48,10 @@ func Test_guild_Check(t *testing.T) {
 			}
 
 			guild := PerGuild(2, 30*time.Second).(*guild)
-			guild.userThrottler.(*user).throttler.throttled[discord.Snowflake(ctx.Author.ID)] = []time.Time{
+			invokeAt(t, guild, ctx,
 				time.Date(2020, 1, 1, 11, 59, 29, 0, time.UTC),
 				time.Date(2020, 1, 1, 11, 59, 50, 0, time.UTC),
-			}
+			)
 
 			cancelFunc, _ := guild.Check(nil, ctx)
 			assert.NotNil(t, cancelFunc)
@@ -67,10 +67,10 @@ func Test_guild_Check(t *testing.T) {
 			ctx := &plugin.Context{Message: discord.Message{GuildID: 123}}
 
 			guild := PerGuild(2, 30*time.Second).(*guild)
-			guild.guildThrottler.throttled[discord.Snowflake(ctx.GuildID)] = []time.Time{
+			invokeAt(t, guild, ctx,
 				time.Date(2020, 1, 1, 11, 59, 40, 0, time.UTC),
 				time.Date(2020, 1, 1, 11, 59, 50, 0, time.UTC),
-			}
+			)
 
 			cancelFunc, err := guild.Check(nil, ctx)
 			assert.Nil(t, cancelFunc)
@@ -85,10 +85,10 @@ func Test_guild_Check(t *testing.T) {
 			}
 
 			guild := PerGuild(2, 30*time.Second).(*guild)
-			guild.guildThrottler.throttled[s] = []time.Time{
+			invokeAt(t, guild, &plugin.Context{Message: discord.Message{GuildID: discord.GuildID(s)}},
 				time.Date(2020, 1, 1, 11, 59, 29, 0, time.UTC),
 				time.Date(2020, 1, 1, 11, 59, 50, 0, time.UTC),
-			}
+			)
 
 			ctx := &plugin.Context{Message: discord.Message{GuildID: 132}}
 
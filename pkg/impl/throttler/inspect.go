@@ -0,0 +1,109 @@
+package throttler
+
+import (
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+type (
+	// Inspector is implemented by the plugin.Throttlers returned by PerUser,
+	// PerMember, PerChannel, and PerGuild, and their variants, as well as by
+	// those returned by Group.
+	// It allows inspecting and modifying the throttling state of an entity,
+	// e.g. to clear the cooldown of a user.
+	//
+	// If the Limiter used by the throttler doesn't implement
+	// LimiterInspector, all methods return ErrNotInspectable.
+	Inspector interface {
+		plugin.Throttler
+
+		// Usage returns the Usage of the passed Target.
+		Usage(t Target) (*Usage, error)
+		// Reset resets the throttling state of the passed Target, as if it
+		// never invoked.
+		Reset(t Target) error
+		// Grant gives back up to the passed number of used invokes to the
+		// passed Target.
+		Grant(t Target, uses uint) error
+	}
+
+	// LimiterInspector is the interface a Limiter must implement so that
+	// throttlers using it implement Inspector.
+	// Its methods behave like the methods of Inspector, but take the key of
+	// the entity instead of a Target.
+	LimiterInspector interface {
+		Usage(key string) (*Usage, error)
+		Reset(key string) error
+		Grant(key string, uses uint) error
+	}
+
+	// Target is the entity whose throttling state is inspected.
+	//
+	// Every throttler uses the ids that it would use when checking an invoke
+	// with the same ids, e.g. throttlers returned by PerMember use GuildID and
+	// UserID, or only UserID, if GuildID is 0.
+	Target struct {
+		UserID    discord.UserID
+		ChannelID discord.ChannelID
+		GuildID   discord.GuildID
+	}
+
+	// Usage is the throttling state of an entity.
+	Usage struct {
+		// Remaining is the number of invokes the entity may make before being
+		// throttled.
+		Remaining uint
+		// ResetIn is the duration until all used invokes are available again.
+		// It is 0, if the entity hasn't used any invokes.
+		ResetIn time.Duration
+	}
+)
+
+// ErrNotInspectable is the error returned by an Inspector, if its Limiter
+// doesn't implement LimiterInspector, or by a Group, if the throttler of the
+// group doesn't implement Inspector.
+var ErrNotInspectable = errors.New("throttler: throttler does not support inspection")
+
+// TargetOf returns the Target of the invoke with the passed *plugin.Context.
+func TargetOf(ctx *plugin.Context) Target {
+	return Target{UserID: ctx.Author.ID, ChannelID: ctx.ChannelID, GuildID: ctx.GuildID}
+}
+
+func limiterInspector(l Limiter) (LimiterInspector, error) {
+	if i, ok := l.(LimiterInspector); ok {
+		return i, nil
+	}
+
+	return nil, ErrNotInspectable
+}
+
+func usage(l Limiter, key string) (*Usage, error) {
+	i, err := limiterInspector(l)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.Usage(key)
+}
+
+func reset(l Limiter, key string) error {
+	i, err := limiterInspector(l)
+	if err != nil {
+		return err
+	}
+
+	return i.Reset(key)
+}
+
+func grant(l Limiter, key string, uses uint) error {
+	i, err := limiterInspector(l)
+	if err != nil {
+		return err
+	}
+
+	return i.Grant(key, uses)
+}
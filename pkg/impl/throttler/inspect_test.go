@@ -0,0 +1,85 @@
+package throttler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+// inspectorLimiters are the LimiterInspectors tested by the Inspector tests.
+// All of them allow 3 invokes per minute.
+var inspectorLimiters = map[string]func() Limiter{
+	"sliding window": func() Limiter { return SlidingWindow(NewMemoryStore(), 3, time.Minute) },
+	"token bucket":   func() Limiter { return TokenBucket(3, 20*time.Second) },
+	"fixed window":   func() Limiter { return FixedWindow(3, time.Minute) },
+}
+
+func TestInspector(t *testing.T) {
+	for name, newLimiter := range inspectorLimiters {
+		newLimiter := newLimiter
+
+		t.Run(name, func(t *testing.T) {
+			now = func() time.Time {
+				return time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+			}
+
+			th := PerMemberWithLimiter(newLimiter()).(Inspector)
+
+			ctx := &plugin.Context{Message: discord.Message{
+				ChannelID: 789,
+				GuildID:   456,
+				Author:    discord.User{ID: 123},
+			}}
+			target := TargetOf(ctx)
+
+			u, err := th.Usage(target)
+			require.NoError(t, err)
+			assert.Equal(t, &Usage{Remaining: 3}, u)
+
+			for i := 0; i < 3; i++ {
+				_, err = th.Check(nil, ctx)
+				require.NoError(t, err)
+			}
+
+			u, err = th.Usage(target)
+			require.NoError(t, err)
+			assert.Equal(t, &Usage{Remaining: 0, ResetIn: time.Minute}, u)
+
+			require.NoError(t, th.Grant(target, 2))
+
+			u, err = th.Usage(target)
+			require.NoError(t, err)
+			assert.Equal(t, uint(2), u.Remaining)
+
+			require.NoError(t, th.Reset(target))
+
+			u, err = th.Usage(target)
+			require.NoError(t, err)
+			assert.Equal(t, &Usage{Remaining: 3}, u)
+		})
+	}
+}
+
+func TestInspector_NotInspectable(t *testing.T) {
+	t.Parallel()
+
+	th := PerUserWithLimiter(new(mockLimiter)).(Inspector)
+
+	_, err := th.Usage(Target{UserID: 123})
+	assert.Equal(t, ErrNotInspectable, err)
+
+	err = th.Reset(Target{UserID: 123})
+	assert.Equal(t, ErrNotInspectable, err)
+
+	err = th.Grant(Target{UserID: 123}, 1)
+	assert.Equal(t, ErrNotInspectable, err)
+}
+
+type mockLimiter struct{}
+
+func (*mockLimiter) Check(string) (func(), time.Duration, error) { return func() {}, 0, nil }
@@ -0,0 +1,153 @@
+package throttler
+
+import (
+	"sync"
+	"time"
+)
+
+// Evicter is implemented by Stores and Limiters that keep their state in
+// memory.
+// Entries are normally only cleaned up, when the entity they belong to
+// invokes again.
+// Evict removes all entries that no longer affect throttling, so that
+// entities that stopped using the bot don't occupy memory forever.
+type Evicter interface {
+	// Evict removes all entries that are expired at the passed time.
+	Evict(now time.Time)
+}
+
+// DefaultJanitor is the Janitor to which all Evicters created by this
+// package are added, i.e. all *MemoryStores, and the Limiters returned by
+// TokenBucket and FixedWindow.
+// They are removed from it again, when they are closed.
+//
+// Unless configured otherwise, bots start it when they are opened, and stop
+// it when they are closed.
+var DefaultJanitor = NewJanitor()
+
+// Janitor periodically evicts the expired entries of the Evicters added to
+// it.
+//
+// Evicters are referenced by the Janitor until they are removed, hence
+// Evicters that are only used temporarily should be removed, once they are
+// no longer needed.
+//
+// A Janitor can be shared by multiple users, e.g. multiple bots, as every
+// call to Start must be matched by a call to Stop, and the Janitor only stops
+// after the last call to Stop.
+type Janitor struct {
+	evicters []Evicter
+	// starts is the number of calls to Start not yet matched by a call to
+	// Stop.
+	starts int
+	// stop is closed to stop the goroutine started by Start.
+	stop chan struct{}
+	// done is closed when the goroutine started by Start returned.
+	done  chan struct{}
+	mutex sync.Mutex
+}
+
+var _ Evicter = new(Janitor)
+
+// NewJanitor creates a new *Janitor with no Evicters.
+func NewJanitor() *Janitor {
+	return new(Janitor)
+}
+
+// Add adds the passed Evicter to the Janitor.
+func (j *Janitor) Add(e Evicter) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	j.evicters = append(j.evicters, e)
+}
+
+// Remove removes the passed Evicter from the Janitor.
+// If the Evicter was never added, Remove is a no-op.
+func (j *Janitor) Remove(e Evicter) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	for i, cmp := range j.evicters {
+		if cmp == e {
+			j.evicters = append(j.evicters[:i], j.evicters[i+1:]...)
+			return
+		}
+	}
+}
+
+// Evict calls Evict on all Evicters of the Janitor.
+func (j *Janitor) Evict(now time.Time) {
+	j.mutex.Lock()
+	evicters := make([]Evicter, len(j.evicters))
+	copy(evicters, j.evicters)
+	j.mutex.Unlock()
+
+	for _, e := range evicters {
+		e.Evict(now)
+	}
+}
+
+// Start starts a goroutine that calls Evict in the passed interval, until
+// Stop was called as often as Start.
+// If the Janitor is already running, it keeps its current interval.
+func (j *Janitor) Start(interval time.Duration) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	j.starts++
+	if j.starts > 1 {
+		return
+	}
+
+	j.stop = make(chan struct{})
+	j.done = make(chan struct{})
+
+	go j.run(interval, j.stop, j.done)
+}
+
+func (j *Janitor) run(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			j.Evict(now())
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Stop undoes a call to Start.
+// If this was the last call to Start not yet matched by a call to Stop, Stop
+// stops the goroutine started by Start, and waits for it to return.
+// If the Janitor isn't running, Stop is a no-op.
+func (j *Janitor) Stop() {
+	j.mutex.Lock()
+
+	if j.starts == 0 {
+		j.mutex.Unlock()
+		return
+	}
+
+	j.starts--
+	if j.starts > 0 {
+		j.mutex.Unlock()
+		return
+	}
+
+	stop, done := j.stop, j.done
+	j.stop, j.done = nil, nil
+	j.mutex.Unlock()
+
+	if stop == nil {
+		return
+	}
+
+	close(stop)
+	<-done
+}
@@ -0,0 +1,122 @@
+package throttler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockEvicter struct {
+	evicted chan time.Time
+}
+
+func newMockEvicter() *mockEvicter {
+	return &mockEvicter{evicted: make(chan time.Time, 1)}
+}
+
+func (e *mockEvicter) Evict(now time.Time) {
+	select {
+	case e.evicted <- now:
+	default:
+	}
+}
+
+func TestJanitor_Evict(t *testing.T) {
+	t.Parallel()
+
+	e1 := newMockEvicter()
+	e2 := newMockEvicter()
+
+	j := NewJanitor()
+	j.Add(e1)
+	j.Add(e2)
+	j.Remove(e2)
+
+	at := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	j.Evict(at)
+
+	select {
+	case actual := <-e1.evicted:
+		assert.Equal(t, at, actual)
+	default:
+		assert.Fail(t, "Evict was not called")
+	}
+
+	select {
+	case <-e2.evicted:
+		assert.Fail(t, "Evict was called on a removed Evicter")
+	default:
+	}
+}
+
+func TestJanitor_Start(t *testing.T) {
+	now = time.Now
+
+	e := newMockEvicter()
+
+	j := NewJanitor()
+	j.Add(e)
+
+	j.Start(time.Millisecond)
+
+	select {
+	case <-e.evicted:
+	case <-time.After(time.Second):
+		assert.Fail(t, "Evict was not called")
+	}
+
+	j.Stop()
+
+	// drain the evict that might have happened between the receive and the
+	// call to Stop
+	select {
+	case <-e.evicted:
+	default:
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	select {
+	case <-e.evicted:
+		assert.Fail(t, "Evict was called after Stop")
+	default:
+	}
+
+	// stopping a stopped Janitor must be a no-op
+	j.Stop()
+}
+
+func TestJanitor_Stop(t *testing.T) {
+	t.Parallel()
+
+	j := NewJanitor()
+
+	j.Start(time.Hour)
+	j.Start(time.Hour)
+
+	j.Stop()
+	assert.NotNil(t, j.stop, "Janitor was stopped before the last call to Stop")
+
+	j.Stop()
+	assert.Nil(t, j.stop, "Janitor was not stopped by the last call to Stop")
+	assert.Equal(t, 0, j.starts)
+
+	// stopping a stopped Janitor must not be counted
+	j.Stop()
+	assert.Equal(t, 0, j.starts)
+}
+
+// contains checks if the passed Evicter was added to the Janitor.
+func (j *Janitor) contains(e Evicter) bool {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	for _, cmp := range j.evicters {
+		if cmp == e {
+			return true
+		}
+	}
+
+	return false
+}
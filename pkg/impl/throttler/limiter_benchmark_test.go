@@ -0,0 +1,76 @@
+package throttler
+
+import (
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+// benchmarkLimiter benchmarks the Limiter created by newLimiter, with
+// invokes evenly distributed among the passed number of distinct snowflakes.
+// Additionally, it reports the heap memory retained per snowflake.
+func benchmarkLimiter(b *testing.B, snowflakes int, newLimiter func() Limiter) {
+	now = time.Now
+
+	keys := make([]string, snowflakes)
+	for i := range keys {
+		keys[i] = storeKey("user", discord.Snowflake(i+1))
+	}
+
+	var before, after runtime.MemStats
+
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	l := newLimiter()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _, _ = l.Check(keys[i%snowflakes])
+	}
+
+	b.StopTimer()
+
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+
+	entries := snowflakes
+	if b.N < entries {
+		entries = b.N
+	}
+
+	b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/float64(entries), "heap-B/snowflake")
+
+	runtime.KeepAlive(l)
+}
+
+func BenchmarkLimiter(b *testing.B) {
+	const maxInvokes = 50
+
+	for _, snowflakes := range []int{1_000, 100_000} {
+		snowflakes := snowflakes
+
+		b.Run("sliding window/"+strconv.Itoa(snowflakes), func(b *testing.B) {
+			benchmarkLimiter(b, snowflakes, func() Limiter {
+				return SlidingWindow(NewMemoryStore(), maxInvokes, time.Hour)
+			})
+		})
+
+		b.Run("token bucket/"+strconv.Itoa(snowflakes), func(b *testing.B) {
+			benchmarkLimiter(b, snowflakes, func() Limiter {
+				return TokenBucket(maxInvokes, time.Hour/maxInvokes)
+			})
+		})
+
+		b.Run("fixed window/"+strconv.Itoa(snowflakes), func(b *testing.B) {
+			benchmarkLimiter(b, snowflakes, func() Limiter {
+				return FixedWindow(maxInvokes, time.Hour)
+			})
+		})
+	}
+}
@@ -1,7 +1,7 @@
 package throttler
 
 import (
-	"sync"
+	"io"
 	"time"
 
 	"github.com/diamondburned/arikawa/v3/discord"
@@ -12,16 +12,15 @@ import (
 
 // member is a plugin.Throttler that works on a per-member basis.
 type member struct {
-	memberThrottler map[discord.GuildID]*snowflakeThrottler
-	userThrottler   plugin.Throttler
-
-	memberMutex sync.Mutex
-
-	maxInvokes uint
-	duration   time.Duration
+	throttler Limiter
+	// closer is the *MemoryStore created by PerMember, or nil.
+	closer io.Closer
 }
 
-var _ plugin.Throttler = new(member)
+var (
+	_ Inspector = new(member)
+	_ io.Closer = new(member)
+)
 
 // PerMember returns a new plugin.Throttler that works on a per-member basis.
 // It allows at maximum the passed number of invokes in the passed duration.
@@ -29,36 +28,73 @@ var _ plugin.Throttler = new(member)
 //
 // All commands invoked in direct messages will be throttled on a per-user
 // basis.
+//
+// The returned plugin.Throttler implements io.Closer, and should be closed,
+// once it is no longer used, to remove its *MemoryStore from the
+// DefaultJanitor.
 func PerMember(maxInvokes uint, duration time.Duration) plugin.Throttler {
-	return &member{
-		memberThrottler: make(map[discord.GuildID]*snowflakeThrottler),
-		userThrottler:   PerUser(maxInvokes, duration),
-		maxInvokes:      maxInvokes,
-		duration:        duration,
-	}
+	s := NewMemoryStore()
+	return &member{throttler: SlidingWindow(s, maxInvokes, duration), closer: s}
 }
 
-func (g *member) Check(s *state.State, ctx *plugin.Context) (func(), error) {
-	if ctx.GuildID == 0 {
-		return g.userThrottler.Check(s, ctx)
-	}
+// PerMemberWithStore is the same as PerMember, but keeps its invokes in the
+// passed Store.
+func PerMemberWithStore(s Store, maxInvokes uint, duration time.Duration) plugin.Throttler {
+	return PerMemberWithLimiter(SlidingWindow(s, maxInvokes, duration))
+}
 
-	g.memberMutex.Lock()
-	defer g.memberMutex.Unlock()
+// PerMemberWithLimiter returns a new plugin.Throttler that works on a
+// per-member basis, using the passed Limiter.
+func PerMemberWithLimiter(l Limiter) plugin.Throttler {
+	return &member{throttler: l}
+}
 
-	gt := g.memberThrottler[ctx.GuildID]
-	if gt == nil {
-		gt = newSnowflakeThrottler(g.maxInvokes, g.duration)
+func (g *member) Check(_ *state.State, ctx *plugin.Context) (func(), error) {
+	if ctx.GuildID == 0 {
+		return checkUser(g.throttler, ctx)
 	}
 
-	cancelFunc, available := gt.check(discord.Snowflake(ctx.Author.ID))
-	g.memberThrottler[ctx.GuildID] = gt
+	cancelFunc, available, err := g.throttler.Check(memberKey(ctx.GuildID, ctx.Author.ID))
+	if err != nil {
+		return nil, err
+	}
 
 	if cancelFunc == nil {
 		return nil, genError(available, memberThrottledErrorSecond, memberThrottledErrorMinute)
 	}
 
-	g.memberThrottler[ctx.GuildID] = gt
-
 	return cancelFunc, nil
 }
+
+func (g *member) Usage(t Target) (*Usage, error) {
+	return usage(g.throttler, memberKey(t.GuildID, t.UserID))
+}
+
+func (g *member) Reset(t Target) error {
+	return reset(g.throttler, memberKey(t.GuildID, t.UserID))
+}
+
+func (g *member) Grant(t Target, uses uint) error {
+	return grant(g.throttler, memberKey(t.GuildID, t.UserID), uses)
+}
+
+// Close closes the *MemoryStore created by PerMember.
+// If the throttler was created using PerMemberWithStore or PerMemberWithLimiter, Close is
+// a no-op.
+func (g *member) Close() error {
+	if g.closer == nil {
+		return nil
+	}
+
+	return g.closer.Close()
+}
+
+// memberKey returns the key of the member with the passed ids.
+// If guildID is 0, the key of the user is returned.
+func memberKey(guildID discord.GuildID, userID discord.UserID) string {
+	if guildID == 0 {
+		return userKey(userID)
+	}
+
+	return storeKey("member", discord.Snowflake(guildID), discord.Snowflake(userID))
+}
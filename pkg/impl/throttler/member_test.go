@@ -25,10 +25,10 @@ func Test_member_Check(t *testing.T) {
 			}
 
 			guild := PerMember(2, 30*time.Second).(*member)
-			guild.userThrottler.(*user).throttler.throttled[discord.Snowflake(ctx.Author.ID)] = []time.Time{
+			invokeAt(t, guild, ctx,
 				time.Date(2020, 1, 1, 11, 59, 40, 0, time.UTC),
 				time.Date(2020, 1, 1, 11, 59, 50, 0, time.UTC),
-			}
+			)
 
 			cancelFunc, err := guild.Check(nil, ctx)
 			assert.Nil(t, cancelFunc)
@@ -48,10 +48,10 @@ func Test_member_Check(t *testing.T) {
 			}
 
 			guild := PerMember(2, 30*time.Second).(*member)
-			guild.userThrottler.(*user).throttler.throttled[discord.Snowflake(ctx.Author.ID)] = []time.Time{
+			invokeAt(t, guild, ctx,
 				time.Date(2020, 1, 1, 11, 59, 29, 0, time.UTC),
 				time.Date(2020, 1, 1, 11, 59, 50, 0, time.UTC),
-			}
+			)
 
 			cancelFunc, _ := guild.Check(nil, ctx)
 			assert.NotNil(t, cancelFunc)
@@ -73,11 +73,10 @@ func Test_member_Check(t *testing.T) {
 
 			guild := PerMember(2, 30*time.Second).(*member)
 
-			guild.memberThrottler[ctx.GuildID] = newSnowflakeThrottler(2, 30*time.Second)
-			guild.memberThrottler[ctx.GuildID].throttled[discord.Snowflake(ctx.Author.ID)] = []time.Time{
+			invokeAt(t, guild, ctx,
 				time.Date(2020, 1, 1, 11, 59, 40, 0, time.UTC),
 				time.Date(2020, 1, 1, 11, 59, 50, 0, time.UTC),
-			}
+			)
 
 			cancelFunc, err := guild.Check(nil, ctx)
 			assert.Nil(t, cancelFunc)
@@ -98,11 +97,10 @@ func Test_member_Check(t *testing.T) {
 
 			guild := PerMember(2, 30*time.Second).(*member)
 
-			guild.memberThrottler[ctx.GuildID] = newSnowflakeThrottler(2, 30*time.Second)
-			guild.memberThrottler[ctx.GuildID].throttled[discord.Snowflake(ctx.Author.ID)] = []time.Time{
+			invokeAt(t, guild, ctx,
 				time.Date(2020, 1, 1, 11, 59, 29, 0, time.UTC),
 				time.Date(2020, 1, 1, 11, 59, 50, 0, time.UTC),
-			}
+			)
 
 			cancelFunc, _ := guild.Check(nil, ctx)
 			assert.NotNil(t, cancelFunc)
@@ -0,0 +1,268 @@
+package throttler
+
+import (
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Store is the storage backend of the throttlers returned by PerUser,
+// PerMember, PerChannel, and PerGuild, and their WithStore variants.
+// It stores the times of the invokes that are still relevant for
+// throttling, grouped by keys.
+//
+// Keys are generated by the throttlers and have the form
+// "<scope>:<id>[:<id>]", e.g. "user:123" or "member:456:123".
+// If a Store is shared by multiple throttlers, e.g. by all commands of a
+// bot, the keys of the throttlers must be namespaced using WithPrefix.
+//
+// All methods of a Store must be atomic and safe for concurrent use.
+type Store interface {
+	// Take removes all invokes stored under the passed key that happened
+	// before expire.
+	// Then, if less than max invokes remain, Take adds an invoke at the
+	// passed time and returns true.
+	// Otherwise, Take returns false, and the time of the oldest remaining
+	// invoke.
+	Take(key string, at, expire time.Time, max uint) (oldest time.Time, ok bool, err error)
+	// Release removes the invoke at the passed time stored under the passed
+	// key.
+	// If there is no such invoke, Release is a no-op.
+	Release(key string, at time.Time) error
+
+	// Invokes returns the invokes stored under the passed key, that
+	// happened at or after expire, in ascending order.
+	Invokes(key string, expire time.Time) ([]time.Time, error)
+	// Remove removes all invokes stored under the passed key that happened
+	// before expire, and then the n oldest remaining invokes.
+	Remove(key string, expire time.Time, n uint) error
+	// Clear removes all invokes stored under the passed key.
+	Clear(key string) error
+}
+
+// =============================================================================
+// MemoryStore
+// =====================================================================================
+
+// MemoryStore is a Store that keeps all invokes in memory.
+// It is the Store used by default.
+//
+// Keys whose invokes all expired are removed by Evict, which is called
+// periodically by the DefaultJanitor.
+// Once a MemoryStore is no longer used, it should be closed, to remove it
+// from the DefaultJanitor.
+type MemoryStore struct {
+	invokes map[string][]time.Time
+	// windows contains the duration of the window of each key, i.e. the
+	// time between at and expire of the last call to Take.
+	windows map[string]time.Duration
+	mutex   sync.Mutex
+}
+
+var (
+	_ Store     = new(MemoryStore)
+	_ Evicter   = new(MemoryStore)
+	_ io.Closer = new(MemoryStore)
+)
+
+// NewMemoryStore creates a new empty *MemoryStore, and adds it to the
+// DefaultJanitor.
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{
+		invokes: make(map[string][]time.Time),
+		windows: make(map[string]time.Duration),
+	}
+
+	DefaultJanitor.Add(s)
+
+	return s
+}
+
+func (s *MemoryStore) Take(key string, at, exp time.Time, max uint) (time.Time, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	invokes, oldest, ok := take(s.invokes[key], at, exp, max)
+	s.windows[key] = at.Sub(exp)
+	s.set(key, invokes)
+
+	return oldest, ok, nil
+}
+
+func (s *MemoryStore) Release(key string, at time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.set(key, release(s.invokes[key], at))
+	return nil
+}
+
+func (s *MemoryStore) Invokes(key string, exp time.Time) ([]time.Time, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return copyInvokes(expire(s.invokes[key], exp)), nil
+}
+
+func (s *MemoryStore) Remove(key string, exp time.Time, n uint) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.set(key, remove(s.invokes[key], exp, n))
+	return nil
+}
+
+func (s *MemoryStore) Clear(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.set(key, nil)
+	return nil
+}
+
+// Evict removes all invokes that are expired at the passed time, and the
+// keys that have no invokes left.
+func (s *MemoryStore) Evict(now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for key, invokes := range s.invokes {
+		s.set(key, expire(invokes, now.Add(-s.windows[key])))
+	}
+}
+
+// Close removes the store from the DefaultJanitor.
+// The store remains usable, but its expired invokes will no longer be
+// evicted.
+func (s *MemoryStore) Close() error {
+	DefaultJanitor.Remove(s)
+	return nil
+}
+
+// set stores the passed invokes under the passed key, removing the key if
+// there are none.
+func (s *MemoryStore) set(key string, invokes []time.Time) {
+	setInvokes(s.invokes, key, invokes)
+
+	if len(invokes) == 0 {
+		delete(s.windows, key)
+	}
+}
+
+// =============================================================================
+// Prefix
+// =====================================================================================
+
+type prefixStore struct {
+	store  Store
+	prefix string
+}
+
+// WithPrefix returns a Store that prefixes all keys with the passed prefix
+// and a colon, before handing them to the passed Store.
+//
+// It is used to share a single Store among multiple throttlers.
+// Each throttler must use a different prefix, e.g. the id of the command it
+// is used for.
+func WithPrefix(s Store, prefix string) Store {
+	return &prefixStore{store: s, prefix: prefix + ":"}
+}
+
+func (s *prefixStore) Take(key string, at, exp time.Time, max uint) (time.Time, bool, error) {
+	return s.store.Take(s.prefix+key, at, exp, max)
+}
+
+func (s *prefixStore) Release(key string, at time.Time) error {
+	return s.store.Release(s.prefix+key, at)
+}
+
+func (s *prefixStore) Invokes(key string, exp time.Time) ([]time.Time, error) {
+	return s.store.Invokes(s.prefix+key, exp)
+}
+
+func (s *prefixStore) Remove(key string, exp time.Time, n uint) error {
+	return s.store.Remove(s.prefix+key, exp, n)
+}
+
+func (s *prefixStore) Clear(key string) error {
+	return s.store.Clear(s.prefix + key)
+}
+
+// =============================================================================
+// Utils
+// =====================================================================================
+
+// expire removes all invokes before the passed time.Time.
+// The invokes must be sorted in ascending order.
+func expire(invokes []time.Time, before time.Time) []time.Time {
+	i := sort.Search(len(invokes), func(i int) bool {
+		return !invokes[i].Before(before)
+	})
+
+	return invokes[i:]
+}
+
+// take implements Store.Take for the passed sorted invokes, and returns the
+// updated invokes.
+func take(invokes []time.Time, at, exp time.Time, max uint) (_ []time.Time, oldest time.Time, ok bool) {
+	invokes = expire(invokes, exp)
+
+	// max reached, prevent invoke
+	if len(invokes) >= int(max) {
+		if len(invokes) > 0 {
+			oldest = invokes[0]
+		}
+
+		return invokes, oldest, false
+	}
+
+	return append(invokes, at), time.Time{}, true
+}
+
+// release implements Store.Release for the passed invokes, and returns the
+// updated invokes.
+func release(invokes []time.Time, at time.Time) []time.Time {
+	for i, cmp := range invokes {
+		if cmp.Equal(at) {
+			return append(invokes[:i], invokes[i+1:]...)
+		}
+	}
+
+	return invokes
+}
+
+// remove implements Store.Remove for the passed sorted invokes, and returns
+// the updated invokes.
+func remove(invokes []time.Time, exp time.Time, n uint) []time.Time {
+	invokes = expire(invokes, exp)
+
+	if int(n) >= len(invokes) {
+		return nil
+	}
+
+	return invokes[n:]
+}
+
+// copyInvokes returns a copy of the passed invokes, or nil if there are
+// none.
+func copyInvokes(invokes []time.Time) []time.Time {
+	if len(invokes) == 0 {
+		return nil
+	}
+
+	cp := make([]time.Time, len(invokes))
+	copy(cp, invokes)
+
+	return cp
+}
+
+// setInvokes stores the passed invokes under the passed key, removing the key
+// if there are none.
+func setInvokes(m map[string][]time.Time, key string, invokes []time.Time) {
+	if len(invokes) == 0 {
+		delete(m, key)
+	} else {
+		m[key] = invokes
+	}
+}
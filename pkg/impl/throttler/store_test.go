@@ -0,0 +1,126 @@
+package throttler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_storeKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "user:123", storeKey("user", 123))
+	assert.Equal(t, "member:456:123", storeKey("member", 456, 123))
+}
+
+func Test_take(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("pass", func(t *testing.T) {
+		t.Parallel()
+
+		invokes := []time.Time{base.Add(-40 * time.Second), base.Add(-10 * time.Second)}
+
+		actual, _, ok := take(invokes, base, base.Add(-30*time.Second), 2)
+		assert.True(t, ok)
+		assert.Equal(t, []time.Time{base.Add(-10 * time.Second), base}, actual)
+	})
+
+	t.Run("blocked", func(t *testing.T) {
+		t.Parallel()
+
+		invokes := []time.Time{base.Add(-20 * time.Second), base.Add(-10 * time.Second)}
+
+		actual, oldest, ok := take(invokes, base, base.Add(-30*time.Second), 2)
+		assert.False(t, ok)
+		assert.Equal(t, base.Add(-20*time.Second), oldest)
+		assert.Equal(t, invokes, actual)
+	})
+}
+
+func Test_release(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	invokes := []time.Time{base, base.Add(time.Second)}
+
+	actual := release(invokes, base.In(time.Local))
+	assert.Equal(t, []time.Time{base.Add(time.Second)}, actual)
+}
+
+func TestWithPrefix(t *testing.T) {
+	t.Parallel()
+
+	s := NewMemoryStore()
+	ps := WithPrefix(s, "abc")
+
+	at := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	_, ok, err := ps.Take("user:123", at, at.Add(-time.Minute), 1)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	assert.Equal(t, []time.Time{at}, s.invokes["abc:user:123"])
+
+	require.NoError(t, ps.Release("user:123", at))
+	assert.Empty(t, s.invokes)
+}
+
+func TestMemoryStore_Evict(t *testing.T) {
+	t.Parallel()
+
+	s := NewMemoryStore()
+	defer s.Close()
+
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 1000; i++ {
+		_, ok, err := s.Take(storeKey("user", discord.Snowflake(i)), base, base.Add(-time.Minute), 2)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	_, ok, err := s.Take("user:0", base.Add(time.Minute), base, 2)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	s.Evict(base.Add(time.Minute + time.Second))
+
+	assert.Equal(t, map[string][]time.Time{"user:0": {base.Add(time.Minute)}}, s.invokes)
+	assert.Equal(t, map[string]time.Duration{"user:0": time.Minute}, s.windows)
+
+	s.Evict(base.Add(2*time.Minute + time.Second))
+
+	assert.Empty(t, s.invokes)
+	assert.Empty(t, s.windows)
+}
+
+func Test_remove(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	invokes := []time.Time{base, base.Add(time.Second), base.Add(2 * time.Second), base.Add(3 * time.Second)}
+
+	actual := remove(invokes, base.Add(time.Second), 1)
+	assert.Equal(t, []time.Time{base.Add(2 * time.Second), base.Add(3 * time.Second)}, actual)
+
+	actual = remove(invokes, base, 5)
+	assert.Nil(t, actual)
+}
+
+func TestMemoryStore_Close(t *testing.T) {
+	t.Parallel()
+
+	s := NewMemoryStore()
+	assert.True(t, DefaultJanitor.contains(s))
+
+	require.NoError(t, s.Close())
+	assert.False(t, DefaultJanitor.contains(s))
+}
@@ -87,3 +87,55 @@ type (
 		Minutes int
 	}
 )
+
+// ================================ tiered ================================
+
+var tieredThrottledError = i18n.NewFallbackConfig(
+	"throttler.tiered.error.throttled",
+	"{{.description}}\nYour tier: {{.tier}}")
+
+type tieredThrottledErrorPlaceholders struct {
+	Description string
+	Tier        string
+}
+
+// ================================ concurrent ================================
+
+// concurrencyErrors are the errors returned by a concurrent throttler, if
+// a limit is reached.
+type concurrencyErrors struct {
+	// limit is used, if the limit is reached, and the invoke couldn't be
+	// queued or timed out while waiting.
+	limit *i18n.Config
+	// queueFull is used, if the queue is full.
+	queueFull *i18n.Config
+}
+
+var (
+	userConcurrencyErrors = &concurrencyErrors{
+		limit: i18n.NewFallbackConfig(
+			"throttler.concurrent.user.error.limit",
+			"You are already using this command. Please wait until it finished."),
+		queueFull: i18n.NewFallbackConfig(
+			"throttler.concurrent.user.error.queue_full",
+			"You have too many pending uses of this command. Please wait until they finished."),
+	}
+
+	channelConcurrencyErrors = &concurrencyErrors{
+		limit: i18n.NewFallbackConfig(
+			"throttler.concurrent.channel.error.limit",
+			"This command is already being used in this channel. Please wait until it finished."),
+		queueFull: i18n.NewFallbackConfig(
+			"throttler.concurrent.channel.error.queue_full",
+			"There are too many pending uses of this command in this channel. Please try again later."),
+	}
+
+	guildConcurrencyErrors = &concurrencyErrors{
+		limit: i18n.NewFallbackConfig(
+			"throttler.concurrent.guild.error.limit",
+			"This command is already being used in this server. Please wait until it finished."),
+		queueFull: i18n.NewFallbackConfig(
+			"throttler.concurrent.guild.error.queue_full",
+			"There are too many pending uses of this command in this server. Please try again later."),
+	}
+)
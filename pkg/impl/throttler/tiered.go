@@ -0,0 +1,172 @@
+package throttler
+
+import (
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/mavolin/disstate/v4/pkg/state"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/i18n"
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+type (
+	// Tiered is a plugin.Throttler that uses different throttlers based on
+	// the roles and permissions of the invoking member, e.g. to give boosters
+	// more invokes than regular members.
+	//
+	// In contrast to Conditional, Tiered doesn't use RestrictionFuncs, and
+	// therefore doesn't need to create restriction errors to find the
+	// matching tier.
+	// The permissions of the invoking member are only computed, if a tier
+	// requires them.
+	Tiered struct {
+		// Tiers are the tiers of the throttler.
+		// The first matching Tier will be used.
+		Tiers []Tier
+		// Default is the throttler used, if no tier matches, or the command
+		// was invoked in a direct message.
+		// If it is nil, users matching no tier won't be throttled.
+		Default plugin.Throttler
+	}
+
+	// Tier is a single tier of a Tiered throttler.
+	//
+	// A member matches a Tier, if they have at least one of the RoleIDs, or
+	// all of the Permissions.
+	// If neither RoleIDs nor Permissions are set, every guild member matches
+	// the Tier.
+	Tier struct {
+		// Name is the optional name of the tier.
+		// If set, it will be included in the description of the
+		// *plugin.ThrottlingError returned, if the member is throttled.
+		Name *i18n.Config
+
+		// RoleIDs are the ids of the roles that grant the tier.
+		RoleIDs []discord.RoleID
+		// Permissions are the permissions that grant the tier.
+		Permissions discord.Permissions
+
+		// Throttler is the plugin.Throttler used for members of the tier.
+		// If it is nil, members of the tier won't be throttled.
+		Throttler plugin.Throttler
+	}
+)
+
+var _ plugin.ReleasingThrottler = Tiered{}
+
+func (t Tiered) Check(s *state.State, ctx *plugin.Context) (func(), error) {
+	tier, err := t.match(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if tier == nil {
+		if t.Default != nil {
+			return t.Default.Check(s, ctx)
+		}
+
+		return func() {}, nil
+	}
+
+	if tier.Throttler == nil {
+		return func() {}, nil
+	}
+
+	cancelFunc, err := tier.Throttler.Check(s, ctx)
+	if err != nil && tier.Name != nil {
+		var terr *plugin.ThrottlingError
+		if errors.As(err, &terr) {
+			return nil, tier.wrapError(ctx.Localizer, terr)
+		}
+	}
+
+	return cancelFunc, err
+}
+
+// Release releases the invoke with the passed context from all throttlers
+// that are plugin.ReleasingThrottlers.
+func (t Tiered) Release(ctx *plugin.Context) {
+	for _, tier := range t.Tiers {
+		releaseThrottler(tier.Throttler, ctx)
+	}
+
+	releaseThrottler(t.Default, ctx)
+}
+
+// match returns the first Tier the invoking member matches, or nil if there
+// is none.
+func (t Tiered) match(ctx *plugin.Context) (*Tier, error) {
+	if ctx.Member == nil {
+		return nil, nil
+	}
+
+	var (
+		perms         discord.Permissions
+		computedPerms bool
+	)
+
+	for i, tier := range t.Tiers {
+		if len(tier.RoleIDs) == 0 && tier.Permissions == 0 {
+			return &t.Tiers[i], nil
+		}
+
+		if hasAnyRole(ctx.Member, tier.RoleIDs) {
+			return &t.Tiers[i], nil
+		}
+
+		if tier.Permissions == 0 {
+			continue
+		}
+
+		if !computedPerms {
+			var err error
+
+			perms, err = ctx.UserPermissions()
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+
+			computedPerms = true
+		}
+
+		if perms.Has(tier.Permissions) {
+			return &t.Tiers[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// wrapError returns a copy of the passed *plugin.ThrottlingError that
+// additionally states the name of the tier.
+func (t *Tier) wrapError(l *i18n.Localizer, terr *plugin.ThrottlingError) error {
+	desc, err := terr.Description(l)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	name, err := l.Localize(t.Name)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return plugin.NewThrottlingErrorl(tieredThrottledError.
+		WithPlaceholders(&tieredThrottledErrorPlaceholders{
+			Description: desc,
+			Tier:        name,
+		}))
+}
+
+// hasAnyRole checks if the passed member has at least one of the roles with
+// the passed ids.
+func hasAnyRole(m *discord.Member, roleIDs []discord.RoleID) bool {
+	for _, target := range roleIDs {
+		for _, id := range m.RoleIDs {
+			if id == target {
+				return true
+			}
+		}
+	}
+
+	return false
+}
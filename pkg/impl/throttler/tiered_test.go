@@ -0,0 +1,98 @@
+package throttler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/i18n"
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+func TestTiered_Check(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		ctx    *plugin.Context
+		expect int
+	}{
+		{
+			name: "role",
+			ctx: &plugin.Context{
+				Message: discord.Message{GuildID: 123},
+				Member:  &discord.Member{RoleIDs: []discord.RoleID{456, 789}},
+			},
+			expect: 1,
+		},
+		{
+			name: "catch all",
+			ctx: &plugin.Context{
+				Message: discord.Message{GuildID: 123},
+				Member:  &discord.Member{RoleIDs: []discord.RoleID{789}},
+			},
+			expect: 2,
+		},
+		{
+			name:   "direct message",
+			ctx:    new(plugin.Context),
+			expect: 3,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			var actual int
+
+			tiered := Tiered{
+				Tiers: []Tier{
+					{RoleIDs: []discord.RoleID{456}, Throttler: &mockThrottler{num: 1, ref: &actual}},
+					{Throttler: &mockThrottler{num: 2, ref: &actual}},
+				},
+				Default: &mockThrottler{num: 3, ref: &actual},
+			}
+
+			_, err := tiered.Check(nil, c.ctx)
+			require.NoError(t, err)
+			assert.Equal(t, c.expect, actual)
+		})
+	}
+}
+
+func TestTiered_Check_Error(t *testing.T) {
+	now = func() time.Time {
+		return time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	}
+
+	tiered := Tiered{
+		Tiers: []Tier{
+			{
+				Name:      i18n.NewFallbackConfig("booster", "Booster"),
+				RoleIDs:   []discord.RoleID{456},
+				Throttler: PerUser(1, time.Minute),
+			},
+		},
+	}
+
+	ctx := &plugin.Context{
+		Message:   discord.Message{GuildID: 123, Author: discord.User{ID: 789}},
+		Member:    &discord.Member{RoleIDs: []discord.RoleID{456}},
+		Localizer: i18n.NewFallbackLocalizer(),
+	}
+
+	_, err := tiered.Check(nil, ctx)
+	require.NoError(t, err)
+
+	_, err = tiered.Check(nil, ctx)
+	require.IsType(t, new(plugin.ThrottlingError), err)
+
+	desc, err := err.(*plugin.ThrottlingError).Description(ctx.Localizer)
+	require.NoError(t, err)
+	assert.Equal(t, "You can use this command again in 60 seconds.\nYour tier: Booster", desc)
+}
@@ -0,0 +1,151 @@
+package throttler
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+type tokenBucket struct {
+	burst uint
+	// interval is the time it takes to refill a single token.
+	interval time.Duration
+	// tolerance is the time it takes to refill all but one token.
+	tolerance time.Duration
+
+	// tats contains the theoretical arrival times of the buckets, i.e. the
+	// time at which the bucket will be full again.
+	tats  map[string]time.Time
+	mutex sync.Mutex
+}
+
+var (
+	_ Limiter          = new(tokenBucket)
+	_ LimiterInspector = new(tokenBucket)
+	_ Evicter          = new(tokenBucket)
+	_ io.Closer        = new(tokenBucket)
+)
+
+// TokenBucket returns a Limiter that gives every entity a bucket of burst
+// tokens.
+// Every invoke takes a token from the bucket, and one token is refilled
+// every refillInterval.
+// If the bucket is empty, the entity is throttled.
+//
+// In contrast to SlidingWindow, TokenBucket only stores a single timestamp
+// per entity, and allows bursts while enforcing a steady rate afterwards.
+// Its state is kept in memory, and full buckets are evicted by the
+// DefaultJanitor.
+// The returned Limiter implements io.Closer, and should be closed, once it
+// is no longer used, to remove it from the DefaultJanitor.
+func TokenBucket(burst uint, refillInterval time.Duration) Limiter {
+	if burst == 0 {
+		burst = 1
+	}
+
+	b := &tokenBucket{
+		burst:     burst,
+		interval:  refillInterval,
+		tolerance: time.Duration(burst-1) * refillInterval,
+		tats:      make(map[string]time.Time),
+	}
+
+	DefaultJanitor.Add(b)
+
+	return b
+}
+
+func (b *tokenBucket) Check(key string) (func(), time.Duration, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := now()
+
+	tat := b.tats[key]
+	if tat.Before(now) {
+		tat = now
+	}
+
+	// bucket empty, prevent invoke
+	if tat.Sub(now) > b.tolerance {
+		return nil, tat.Sub(now) - b.tolerance, nil
+	}
+
+	b.tats[key] = tat.Add(b.interval)
+
+	return func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+
+		if tat, ok := b.tats[key]; ok {
+			b.tats[key] = tat.Add(-b.interval)
+		}
+	}, 0, nil
+}
+
+func (b *tokenBucket) Usage(key string) (*Usage, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := now()
+
+	tat := b.tats[key]
+	if !tat.After(now) {
+		return &Usage{Remaining: b.burst}, nil
+	}
+
+	u := Usage{ResetIn: tat.Sub(now)}
+
+	// every started interval is a used token
+	if used := uint((u.ResetIn + b.interval - 1) / b.interval); used < b.burst {
+		u.Remaining = b.burst - used
+	}
+
+	return &u, nil
+}
+
+func (b *tokenBucket) Reset(key string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delete(b.tats, key)
+	return nil
+}
+
+func (b *tokenBucket) Grant(key string, uses uint) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	tat, ok := b.tats[key]
+	if !ok {
+		return nil
+	}
+
+	tat = tat.Add(-time.Duration(uses) * b.interval)
+	if tat.After(now()) {
+		b.tats[key] = tat
+	} else {
+		delete(b.tats, key)
+	}
+
+	return nil
+}
+
+// Evict removes the buckets that are full at the passed time, as they are
+// indistinguishable from new buckets.
+func (b *tokenBucket) Evict(now time.Time) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for key, tat := range b.tats {
+		if !tat.After(now) {
+			delete(b.tats, key)
+		}
+	}
+}
+
+// Close removes the Limiter from the DefaultJanitor.
+func (b *tokenBucket) Close() error {
+	DefaultJanitor.Remove(b)
+	return nil
+}
@@ -0,0 +1,97 @@
+package throttler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_tokenBucket_Check(t *testing.T) {
+	t.Run("burst", func(t *testing.T) {
+		now = func() time.Time {
+			return time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+		}
+
+		b := TokenBucket(3, 10*time.Second)
+
+		for i := 0; i < 3; i++ {
+			cancelFunc, _, err := b.Check("user:123")
+			require.NoError(t, err)
+			require.NotNil(t, cancelFunc, "invoke %d", i)
+		}
+
+		cancelFunc, available, err := b.Check("user:123")
+		require.NoError(t, err)
+		assert.Nil(t, cancelFunc)
+		assert.Equal(t, 10*time.Second, available)
+	})
+
+	t.Run("refill", func(t *testing.T) {
+		now = func() time.Time {
+			return time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+		}
+
+		b := TokenBucket(1, 10*time.Second)
+
+		cancelFunc, _, err := b.Check("user:123")
+		require.NoError(t, err)
+		require.NotNil(t, cancelFunc)
+
+		now = func() time.Time {
+			return time.Date(2020, 1, 1, 12, 0, 4, 0, time.UTC)
+		}
+
+		cancelFunc, available, err := b.Check("user:123")
+		require.NoError(t, err)
+		require.Nil(t, cancelFunc)
+		assert.Equal(t, 6*time.Second, available)
+
+		now = func() time.Time {
+			return time.Date(2020, 1, 1, 12, 0, 10, 0, time.UTC)
+		}
+
+		cancelFunc, _, err = b.Check("user:123")
+		require.NoError(t, err)
+		assert.NotNil(t, cancelFunc)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		now = func() time.Time {
+			return time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+		}
+
+		b := TokenBucket(1, 10*time.Second)
+
+		cancelFunc, _, err := b.Check("user:123")
+		require.NoError(t, err)
+		require.NotNil(t, cancelFunc)
+
+		cancelFunc()
+
+		cancelFunc, _, err = b.Check("user:123")
+		require.NoError(t, err)
+		assert.NotNil(t, cancelFunc)
+	})
+}
+
+func Test_tokenBucket_Evict(t *testing.T) {
+	now = func() time.Time {
+		return time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	}
+
+	b := TokenBucket(2, 10*time.Second).(*tokenBucket)
+
+	for i := 0; i < 1000; i++ {
+		_, _, err := b.Check(storeKey("user", discord.Snowflake(i)))
+		require.NoError(t, err)
+	}
+
+	b.Evict(time.Date(2020, 1, 1, 12, 0, 9, 0, time.UTC))
+	assert.Len(t, b.tats, 1000)
+
+	b.Evict(time.Date(2020, 1, 1, 12, 0, 10, 0, time.UTC))
+	assert.Empty(t, b.tats)
+}
@@ -1,6 +1,7 @@
 package throttler
 
 import (
+	"io"
 	"time"
 
 	"github.com/diamondburned/arikawa/v3/discord"
@@ -11,19 +12,78 @@ import (
 
 // user is a plugin.Throttler that works on a per-user basis.
 type user struct {
-	throttler *snowflakeThrottler
+	throttler Limiter
+	// closer is the *MemoryStore created by PerUser, or nil.
+	closer io.Closer
 }
 
-var _ plugin.Throttler = new(user)
+var (
+	_ Inspector = new(user)
+	_ io.Closer = new(user)
+)
 
 // PerUser returns a new plugin.Throttler that works on a per-user basis.
 // It allows at maximum the passed number of invokes in the passed duration.
+//
+// The returned plugin.Throttler implements io.Closer, and should be closed,
+// once it is no longer used, to remove its *MemoryStore from the
+// DefaultJanitor.
 func PerUser(maxInvokes uint, duration time.Duration) plugin.Throttler {
-	return &user{throttler: newSnowflakeThrottler(maxInvokes, duration)}
+	s := NewMemoryStore()
+	return &user{throttler: SlidingWindow(s, maxInvokes, duration), closer: s}
+}
+
+// PerUserWithStore is the same as PerUser, but keeps its invokes in the
+// passed Store.
+func PerUserWithStore(s Store, maxInvokes uint, duration time.Duration) plugin.Throttler {
+	return PerUserWithLimiter(SlidingWindow(s, maxInvokes, duration))
+}
+
+// PerUserWithLimiter returns a new plugin.Throttler that works on a
+// per-user basis, using the passed Limiter.
+func PerUserWithLimiter(l Limiter) plugin.Throttler {
+	return &user{throttler: l}
 }
 
 func (g *user) Check(_ *state.State, ctx *plugin.Context) (func(), error) {
-	cancelFunc, available := g.throttler.check(discord.Snowflake(ctx.Author.ID))
+	return checkUser(g.throttler, ctx)
+}
+
+func (g *user) Usage(t Target) (*Usage, error) {
+	return usage(g.throttler, userKey(t.UserID))
+}
+
+func (g *user) Reset(t Target) error {
+	return reset(g.throttler, userKey(t.UserID))
+}
+
+func (g *user) Grant(t Target, uses uint) error {
+	return grant(g.throttler, userKey(t.UserID), uses)
+}
+
+// Close closes the *MemoryStore created by PerUser.
+// If the throttler was created using PerUserWithStore or PerUserWithLimiter, Close is
+// a no-op.
+func (g *user) Close() error {
+	if g.closer == nil {
+		return nil
+	}
+
+	return g.closer.Close()
+}
+
+// userKey returns the key of the user with the passed id.
+func userKey(userID discord.UserID) string {
+	return storeKey("user", discord.Snowflake(userID))
+}
+
+// checkUser checks if the invoking user should be throttled using the passed
+// Limiter.
+func checkUser(t Limiter, ctx *plugin.Context) (func(), error) {
+	cancelFunc, available, err := t.Check(userKey(ctx.Author.ID))
+	if err != nil {
+		return nil, err
+	}
 
 	if cancelFunc == nil {
 		return nil, genError(available, userThrottledErrorSecond, userThrottledErrorMinute)
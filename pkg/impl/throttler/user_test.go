@@ -21,10 +21,10 @@ func Test_user_Check(t *testing.T) {
 		}
 
 		user := PerUser(2, 30*time.Second).(*user)
-		user.throttler.throttled[discord.Snowflake(ctx.Author.ID)] = []time.Time{
+		invokeAt(t, user, ctx,
 			time.Date(2020, 1, 1, 11, 59, 40, 0, time.UTC),
 			time.Date(2020, 1, 1, 11, 59, 50, 0, time.UTC),
-		}
+		)
 
 		cancelFunc, err := user.Check(nil, ctx)
 		assert.Nil(t, cancelFunc)
@@ -43,12 +43,22 @@ func Test_user_Check(t *testing.T) {
 		}
 
 		user := PerUser(2, 30*time.Second).(*user)
-		user.throttler.throttled[discord.Snowflake(ctx.Author.ID)] = []time.Time{
+		invokeAt(t, user, ctx,
 			time.Date(2020, 1, 1, 11, 59, 29, 0, time.UTC),
 			time.Date(2020, 1, 1, 11, 59, 50, 0, time.UTC),
-		}
+		)
 
 		cancelFunc, _ := user.Check(nil, ctx)
 		assert.NotNil(t, cancelFunc)
 	})
 }
+
+func Test_user_Close(t *testing.T) {
+	t.Parallel()
+
+	user := PerUser(2, 30*time.Second).(*user)
+	assert.True(t, DefaultJanitor.contains(user.closer.(*MemoryStore)))
+
+	assert.NoError(t, user.Close())
+	assert.False(t, DefaultJanitor.contains(user.closer.(*MemoryStore)))
+}
@@ -133,8 +133,32 @@ type (
 		// It must return a value that is of the type returned by Parse.
 		GetDefault() interface{}
 	}
+
+	// CompletingArgType is an ArgType that is able to suggest values for a
+	// partial input.
+	// Implementing it is optional.
+	//
+	// Completions are used to give users a hint, if parsing fails, and to
+	// answer the autocomplete interactions of application commands.
+	CompletingArgType interface {
+		ArgType
+
+		// Complete returns at most max Completions for the partial input
+		// found in ctx.Raw, ordered by relevance.
+		// If there are no matching values, Complete should return nil, nil.
+		Complete(s *state.State, ctx *ParseContext, max int) ([]Completion, error)
+	}
 )
 
+// Completion is a value suggested by a CompletingArgType.
+type Completion struct {
+	// Name is the name of the value, as displayed to the user.
+	Name string
+	// Value is the raw value, that, if parsed by the CompletingArgType,
+	// results in the suggested value.
+	Value string
+}
+
 // ArgKind specifies whether a flag or an argument is being parsed.
 type ArgKind string
 
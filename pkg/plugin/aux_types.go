@@ -135,6 +135,22 @@ func (t ChannelTypes) String() string {
 // Implementations can be found in impl/restriction.
 type RestrictionFunc func(*state.State, *Context) error
 
+// RestrictionProvider provides restrictions that are configured at runtime,
+// e.g. by the admins of a guild.
+// It is consulted by ResolvedCommand.IsRestricted, in addition to the
+// restrictions defined by the command and its parents.
+//
+// Implementations can be found in impl/restriction.
+type RestrictionProvider interface {
+	// Restrictions returns the restrictions of the plugin with the passed
+	// id in the guild with the passed id.
+	// If the plugin is invoked in a direct message, guildID is 0.
+	//
+	// If there are no restrictions for the plugin, Restrictions should
+	// return nil, nil.
+	Restrictions(guildID discord.GuildID, pluginID ID) (RestrictionFunc, error)
+}
+
 // Throttler is used to create cooldowns for commands.
 //
 // Implementations can be found in impl/throttler.
@@ -156,3 +172,20 @@ type Throttler interface {
 	// available.
 	Check(*state.State, *Context) (func(), error)
 }
+
+// ReleasingThrottler is a Throttler whose invokes only count while the
+// command is running, e.g. to limit the number of concurrent invokes.
+//
+// Implementations can be found in impl/throttler.
+type ReleasingThrottler interface {
+	Throttler
+
+	// Release is called after the command of the invoke with the passed
+	// Context returned, regardless of whether it was successful.
+	//
+	// Release must be a no-op, if the invoke wasn't counted by Check, or
+	// was already canceled or released.
+	// This allows throttlers wrapping other throttlers to call Release on
+	// all of them.
+	Release(*Context)
+}
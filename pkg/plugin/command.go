@@ -1,6 +1,8 @@
 package plugin
 
 import (
+	"time"
+
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/mavolin/disstate/v4/pkg/state"
 
@@ -96,6 +98,15 @@ type (
 		GetThrottler() Throttler
 	}
 
+	// Timeouter is an optional interface that can be implemented by a
+	// Command, to limit the duration of its invokes.
+	Timeouter interface {
+		// GetTimeout returns the maximum duration an invoke of the command
+		// may take, after which Context.Ctx gets canceled.
+		// If it is 0, the command doesn't time out.
+		GetTimeout() time.Duration
+	}
+
 	// ExampleArgs is a struct containing a set of exemplary arguments and
 	// flags.
 	// They are formatted using their ArgParser's FormatArgs method.
@@ -167,17 +178,28 @@ type ResolvedCommand interface {
 	IsHidden() bool
 	// ChannelTypes are the ChannelTypes this command can be run in.
 	//
-	// If the command itself did not define some, ChannelTypes will be
-	// AllChannels.
+	// If the command itself did not define some, the ChannelTypes of the
+	// closest of its SourceParents that defines some are used.
+	// If none of them do, ChannelTypes will be AllChannels.
 	ChannelTypes() ChannelTypes
 	// BotPermissions returns the permissions the command needs to execute.
+	// They include the permissions required by its SourceParents.
 	BotPermissions() discord.Permissions
 	// IsRestricted checks whether or not this command is restricted.
+	// It checks the restrictions of its SourceParents, starting with the
+	// most distant one, the restrictions of the command, and finally the
+	// restrictions of the Context's RestrictionProvider.
+	// The first restriction returning an error aborts the check.
 	//
 	// If the RestrictionFunc returns an error that implements
 	// RestrictionErrorWrapper, it will be wrapped accordingly.
 	IsRestricted(*state.State, *Context) error
 	// Throttler returns the Throttler of this command.
+	// It combines the throttler of the command with the throttlers of its
+	// SourceParents, and is only considered passed if all of them pass.
+	//
+	// If neither the command nor its parents are throttled, Throttler
+	// returns nil.
 	Throttler() Throttler
 
 	// Invoke invokes the command.
@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -11,21 +12,44 @@ import (
 
 	"github.com/mavolin/adam/internal/shared"
 	"github.com/mavolin/adam/pkg/i18n"
+	"github.com/mavolin/adam/pkg/utils/logutil"
 	"github.com/mavolin/adam/pkg/utils/permutil"
 )
 
 // Context contains context information about a command.
 type Context struct {
 	// Message is the invoking message.
+	//
+	// If the command was invoked through an application command, Message is
+	// synthesized from the interaction.
+	// In that case, it has no id and its Content is the invoke of the
+	// command, as found in the interaction's data.
 	discord.Message
 	// Member is the invoking member, or nil if the command was invoked in a
 	// direct message.
 	*discord.Member
 
-	// Base is the *event.Base of the MessageCreateEvent or MessageUpdateEvent
-	// that triggered the invoke.
+	// Base is the *event.Base of the MessageCreateEvent, MessageUpdateEvent,
+	// or InteractionCreateEvent that triggered the invoke.
 	*event.Base
 
+	// Interaction is the interaction that triggered the invoke, or nil if the
+	// command was invoked through a message.
+	Interaction *discord.InteractionEvent
+
+	// Ctx is the context.Context of the invoke.
+	// It is canceled if the command exceeds its timeout, or if the bot is
+	// closed.
+	// Long-running commands should respect it.
+	Ctx context.Context
+
+	// Trace contains the Spans recorded during the invoke.
+	// It is nil, if tracing is disabled.
+	//
+	// Commands may record their own Spans, to further break down their
+	// duration.
+	Trace *Trace
+
 	// Localizer is the localizer set to the guild's or user's language.
 	*i18n.Localizer
 
@@ -62,9 +86,22 @@ type Context struct {
 	// bot.Options.
 	BotOwnerIDs []discord.UserID
 
+	// Logger is the logutil.Logger used to log errors that cannot be
+	// reported to the user, as defined in the bot's bot.Options.
+	// It may be nil, in which case logutil.Std should be used.
+	Logger logutil.Logger
+
+	// RestrictionProvider is the RestrictionProvider consulted by
+	// ResolvedCommand.IsRestricted, as defined in the bot's bot.Options.
+	// It may be nil, in which case only the restrictions of the command
+	// and its parents are checked.
+	RestrictionProvider RestrictionProvider
+
 	// Replier is the interface used to send replies to a command.
 	//
-	// Defaults to replier.WrapState, as found in impl/replier.
+	// Defaults to replier.WrapState, as found in impl/replier, or to
+	// replier.WrapInteraction, if the command was invoked through an
+	// interaction.
 	Replier Replier
 
 	// Provider is an embedded interface that provides access to the commands
@@ -94,6 +131,12 @@ func (ctx *Context) IsBotOwner() bool {
 	return false
 }
 
+// IsInteraction checks if the command was invoked through an application
+// command.
+func (ctx *Context) IsInteraction() bool {
+	return ctx.Interaction != nil
+}
+
 // UsedPrefix returns the prefix used to invoke the command.
 func (ctx *Context) UsedPrefix() string {
 	return strings.TrimRight(ctx.Content[:ctx.InvokeIndex], shared.Whitespace)
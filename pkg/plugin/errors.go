@@ -21,6 +21,8 @@ import (
 // invalid.
 type ArgumentError struct {
 	desc *i18n.Config
+	// suggestions are the values the user might have meant.
+	suggestions []string
 }
 
 // NewArgumentError returns a new *ArgumentError with the passed
@@ -42,6 +44,21 @@ func (e *ArgumentError) Description(l *i18n.Localizer) (string, error) {
 	return l.Localize(e.desc)
 }
 
+// WithSuggestions returns a copy of the ArgumentError, that suggests the
+// passed values to the user, as a "did you mean" hint.
+// Typically, these are the names of the Completions of a CompletingArgType.
+func (e *ArgumentError) WithSuggestions(suggestions ...string) *ArgumentError {
+	cp := *e
+	cp.suggestions = suggestions
+
+	return &cp
+}
+
+// Suggestions returns the values suggested to the user, if any.
+func (e *ArgumentError) Suggestions() []string {
+	return e.suggestions
+}
+
 func (e *ArgumentError) Error() string {
 	return "argument error"
 }
@@ -60,6 +77,23 @@ var HandleArgumentError = func(_ *state.State, ctx *Context, aerr *ArgumentError
 		return err
 	}
 
+	if len(aerr.suggestions) > 0 {
+		suggestions := make([]string, len(aerr.suggestions))
+		for i, s := range aerr.suggestions {
+			suggestions[i] = "`" + s + "`"
+		}
+
+		hint, err := ctx.Localize(argumentErrorSuggestions.
+			WithPlaceholders(argumentErrorSuggestionsPlaceholders{
+				Suggestions: strings.Join(suggestions, ", "),
+			}))
+		if err != nil {
+			return err
+		}
+
+		desc += "\n\n" + hint
+	}
+
 	e := shared.ErrorEmbedTemplate(ctx.Localizer)
 	e.Description = desc
 
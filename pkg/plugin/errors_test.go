@@ -83,6 +83,35 @@ func TestArgumentParsingError_Handle(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestArgumentParsingError_Handle_suggestions(t *testing.T) {
+	t.Parallel()
+
+	var channelID discord.ChannelID = 123
+
+	m, s := state.NewMocker(t)
+
+	ctx := &Context{
+		Message:   discord.Message{ChannelID: channelID},
+		Localizer: i18n.NewFallbackLocalizer(),
+		Replier:   newMockedWrappedReplier(s, 123, 0),
+	}
+
+	expectEmbed := shared.ErrorEmbedTemplate(ctx.Localizer)
+	expectEmbed.Description = "abc\n\nDid you mean `def`, `ghi`?"
+
+	m.SendEmbeds(discord.Message{
+		ChannelID: channelID,
+		Embeds: []discord.Embed{
+			expectEmbed,
+		},
+	})
+
+	e := NewArgumentError("abc").WithSuggestions("def", "ghi")
+
+	err := e.Handle(s, ctx)
+	require.NoError(t, err)
+}
+
 // =============================================================================
 // BotPermissionsError
 // =====================================================================================
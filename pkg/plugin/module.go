@@ -1,6 +1,9 @@
 package plugin
 
 import (
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/mavolin/disstate/v4/pkg/state"
+
 	"github.com/mavolin/adam/pkg/i18n"
 )
 
@@ -30,6 +33,54 @@ type (
 		// GetLongDescription returns an option long description of the module.
 		GetLongDescription(l *i18n.Localizer) string
 	}
+
+	// ModuleThrottler is an optional interface that can be implemented by a
+	// Module, to throttle all of its commands.
+	ModuleThrottler interface {
+		// GetThrottler returns the Throttler of the module.
+		// It applies to all commands of the module and its submodules, in
+		// addition to their own throttlers.
+		//
+		// If the module is not throttled, GetThrottler returns nil.
+		GetThrottler() Throttler
+	}
+
+	// ModuleChannelTyper is an optional interface that can be implemented by
+	// a Module, to limit the channels its commands may be invoked in.
+	ModuleChannelTyper interface {
+		// GetChannelTypes returns the ChannelTypes the commands of the module
+		// and its submodules may be invoked in.
+		// It is only used for commands that don't define their own
+		// ChannelTypes.
+		// If multiple parent modules define ChannelTypes, the ChannelTypes of
+		// the closest parent are used.
+		//
+		// If this is 0, the ChannelTypes of the module's parent will be used.
+		GetChannelTypes() ChannelTypes
+	}
+
+	// ModuleBotPermissioner is an optional interface that can be implemented
+	// by a Module, to require permissions for all of its commands.
+	ModuleBotPermissioner interface {
+		// GetBotPermissions returns the permissions the bot needs to execute
+		// the commands of the module and its submodules.
+		// They are combined with the permissions required by the commands
+		// themselves.
+		GetBotPermissions() discord.Permissions
+	}
+
+	// ModuleRestricter is an optional interface that can be implemented by a
+	// Module, to restrict all of its commands.
+	ModuleRestricter interface {
+		// IsRestricted checks if the user is restricted from using the
+		// commands of the module and its submodules.
+		// The restrictions of a module are checked before those of its
+		// submodules and commands, and all of them must pass.
+		//
+		// If they are restricted, a *plugin.RestrictionError should be
+		// returned.
+		IsRestricted(s *state.State, ctx *Context) error
+	}
 )
 
 type (
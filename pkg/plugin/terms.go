@@ -2,6 +2,16 @@ package plugin
 
 import "github.com/mavolin/adam/pkg/i18n"
 
+// ================================ ArgumentError ================================
+
+var argumentErrorSuggestions = i18n.NewFallbackConfig(
+	"plugin.error.argument.suggestions",
+	"Did you mean {{.suggestions}}?")
+
+type argumentErrorSuggestionsPlaceholders struct {
+	Suggestions string
+}
+
 // ================================ BotPermissionsError ================================
 
 var (
@@ -0,0 +1,78 @@
+package plugin
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Trace contains the Spans recorded during a single invoke.
+//
+// A nil *Trace is valid and discards all recorded Spans.
+// This allows commands to record their own Spans, regardless of whether
+// tracing is enabled.
+//
+// Trace is safe for concurrent use.
+type Trace struct {
+	mut   sync.Mutex
+	spans []Span
+}
+
+// Span is a named, timed section of an invoke, typically a single middleware.
+type Span struct {
+	// Name is the name of the span.
+	Name string
+	// Start is the time the span started.
+	Start time.Time
+	// End is the time the span ended.
+	End time.Time
+	// Downstream is the time spent in the middlewares and the command called
+	// by the span's middleware, i.e. the time spent in nested spans.
+	Downstream time.Duration
+}
+
+// Duration returns the total duration of the span, including the time spent
+// downstream.
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// SelfDuration returns the duration of the span, excluding the time spent
+// downstream.
+func (s Span) SelfDuration() time.Duration {
+	return s.Duration() - s.Downstream
+}
+
+// NewTrace creates a new empty *Trace.
+func NewTrace() *Trace {
+	return new(Trace)
+}
+
+// Record records the passed Span.
+func (t *Trace) Record(s Span) {
+	if t == nil {
+		return
+	}
+
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	t.spans = append(t.spans, s)
+}
+
+// Spans returns a copy of the recorded Spans sorted by their start time.
+func (t *Trace) Spans() []Span {
+	if t == nil {
+		return nil
+	}
+
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	spans := make([]Span, len(t.spans))
+	copy(spans, t.spans)
+
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Start.Before(spans[j].Start) })
+
+	return spans
+}
@@ -0,0 +1,45 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpan_SelfDuration(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+
+	s := Span{Start: start, End: start.Add(3 * time.Second), Downstream: time.Second}
+	assert.Equal(t, 3*time.Second, s.Duration())
+	assert.Equal(t, 2*time.Second, s.SelfDuration())
+}
+
+func TestTrace_Spans(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		var tr *Trace
+		tr.Record(Span{Name: "abc"})
+		assert.Nil(t, tr.Spans())
+	})
+
+	t.Run("sorted", func(t *testing.T) {
+		t.Parallel()
+
+		start := time.Now()
+
+		inner := Span{Name: "inner", Start: start.Add(time.Second)}
+		outer := Span{Name: "outer", Start: start}
+
+		tr := NewTrace()
+		tr.Record(inner)
+		tr.Record(outer)
+
+		assert.Equal(t, []Span{outer, inner}, tr.Spans())
+	})
+}
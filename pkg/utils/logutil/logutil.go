@@ -0,0 +1,100 @@
+// Package logutil provides the structured logger used by adam to log errors
+// that cannot be reported to the user.
+package logutil
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/mavolin/adam/internal/errorutil"
+)
+
+// Field is a key-value pair attached to a log entry.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F creates a new Field with the passed key and value.
+func F(key string, val interface{}) Field {
+	return Field{Key: key, Value: val}
+}
+
+// Logger is the interface of a structured logger.
+//
+// Implementations typically wrap a logging library, such as zap or logrus,
+// and map the Fields to the library's own field type.
+//
+// Values of Fields are either basic types, discord snowflakes, errors, or
+// stack traces.
+// Stack traces are of type errors.StackTrace and implement
+// encoding.TextMarshaler for each of their frames, so that JSON encoders
+// render them as a list of strings.
+type Logger interface {
+	// Error logs an error with the passed message and Fields.
+	Error(msg string, fields ...Field)
+}
+
+// Std is the Logger used by default.
+// It logs using the log package of the standard library.
+//
+// Fields are appended to the message as space separated key=value pairs.
+// Stack traces are printed on the lines following the entry, one frame per
+// line.
+var Std Logger = new(stdLogger)
+
+type stdLogger struct{}
+
+func (*stdLogger) Error(msg string, fields ...Field) {
+	var b strings.Builder
+	b.WriteString(msg)
+
+	var stack errorutil.StackTrace
+
+	for _, f := range fields {
+		if s, ok := f.Value.(errorutil.StackTrace); ok {
+			stack = s
+			continue
+		}
+
+		b.WriteRune(' ')
+		b.WriteString(f.Key)
+		b.WriteRune('=')
+		b.WriteString(formatValue(f.Value))
+	}
+
+	if len(stack) > 0 {
+		fmt.Fprintf(&b, "%+v", stack)
+	}
+
+	log.Println(b.String())
+}
+
+// formatValue formats the passed value, quoting it if it contains
+// whitespace, quotes, or equal signs.
+func formatValue(val interface{}) string {
+	s := fmt.Sprint(val)
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
+
+// ErrorFields returns the Fields describing the passed error.
+// Besides the error itself, stored under the key "error", it also includes
+// the stack trace of the error, stored under the key "stack", if there is
+// one.
+func ErrorFields(err error) []Field {
+	fields := []Field{F("error", err)}
+
+	var tracer interface{ StackTrace() errorutil.StackTrace }
+	if errors.As(err, &tracer) {
+		fields = append(fields, F("stack", tracer.StackTrace()))
+	}
+
+	return fields
+}
@@ -0,0 +1,63 @@
+package logutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mavolin/adam/internal/errorutil"
+)
+
+func TestFormatValue(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		val    interface{}
+		expect string
+	}{
+		{name: "plain", val: "abc", expect: "abc"},
+		{name: "number", val: 123, expect: "123"},
+		{name: "empty", val: "", expect: `""`},
+		{name: "space", val: "abc def", expect: `"abc def"`},
+		{name: "quote", val: `a"b`, expect: `"a\"b"`},
+		{name: "equal sign", val: "a=b", expect: `"a=b"`},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := formatValue(c.val)
+			assert.Equal(t, c.expect, actual)
+		})
+	}
+}
+
+func TestErrorFields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no stack trace", func(t *testing.T) {
+		t.Parallel()
+
+		err := errors.New("abc")
+
+		expect := []Field{F("error", err)}
+
+		actual := ErrorFields(err)
+		assert.Equal(t, expect, actual)
+	})
+
+	t.Run("stack trace", func(t *testing.T) {
+		t.Parallel()
+
+		err := errorutil.WithStack(errors.New("abc")).(*errorutil.StackError)
+
+		expect := []Field{F("error", err), F("stack", err.StackTrace())}
+
+		actual := ErrorFields(err)
+		assert.Equal(t, expect, actual)
+	})
+}
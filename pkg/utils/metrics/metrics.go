@@ -0,0 +1,228 @@
+// Package metrics provides a Collector recording metrics about command
+// invokes, that can be exported in the Prometheus text format.
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mavolin/disstate/v4/pkg/state"
+
+	"github.com/mavolin/adam/pkg/bot"
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+// DefaultBuckets are the default upper bounds of the latency histogram
+// buckets, in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// ErrorKind is the kind of error returned by a command invoke.
+type ErrorKind string
+
+const (
+	// UserErrorKind is the kind of *errors.UserError, *errors.UserInfo,
+	// *plugin.BotPermissionsError and *plugin.ChannelTypeError.
+	UserErrorKind ErrorKind = "user"
+	// InternalErrorKind is the kind of all errors that are of no other kind,
+	// most notably *errors.InternalError.
+	InternalErrorKind ErrorKind = "internal"
+	// RestrictionErrorKind is the kind of *plugin.RestrictionError.
+	RestrictionErrorKind ErrorKind = "restriction"
+	// ThrottlingErrorKind is the kind of *plugin.ThrottlingError.
+	ThrottlingErrorKind ErrorKind = "throttling"
+	// ArgumentErrorKind is the kind of *plugin.ArgumentError.
+	ArgumentErrorKind ErrorKind = "argument"
+)
+
+// Collector collects metrics about command invokes.
+// All metrics are keyed by the plugin.ID of the invoked command.
+//
+// To collect all metrics, the Collector must be installed in three places:
+//
+//	c := metrics.NewCollector()
+//
+//	b, err := bot.New(bot.Options{
+//		// ...
+//		ErrorHandler: c.WrapErrorHandler(bot.DefaultErrorHandler),
+//	})
+//
+//	b.AddMiddleware(c.Middleware)
+//	b.AddPostMiddleware(c.PostMiddleware)
+//
+// Middleware must be added after the default middlewares, so that the
+// command is already found when it is called.
+// PostMiddleware must be added after the default post middlewares, so that it
+// is only reached if the command returned successfully.
+//
+// Collector is an http.Handler, serving the collected metrics in the
+// Prometheus text format.
+//
+// Collector is safe for concurrent use.
+type Collector struct {
+	buckets []float64
+
+	mut      sync.Mutex
+	commands map[plugin.ID]*commandMetrics
+}
+
+type commandMetrics struct {
+	invokes    uint64
+	successes  uint64
+	throttled  uint64
+	restricted uint64
+	errors     map[ErrorKind]uint64
+
+	// bucketCounts contains the number of observations per bucket.
+	// Unlike in the exported histogram, the counts are not cumulative.
+	bucketCounts []uint64
+	durationSum  float64
+}
+
+// NewCollector creates a new *Collector using the passed upper bounds for
+// its latency histogram buckets, in seconds.
+// If no buckets are given, DefaultBuckets will be used.
+func NewCollector(buckets ...float64) *Collector {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+
+	return &Collector{
+		buckets:  sorted,
+		commands: make(map[plugin.ID]*commandMetrics),
+	}
+}
+
+// Middleware is the bot.Middleware counting invokes and recording their
+// latency.
+// The latency is measured from the moment Middleware is called until all
+// subsequent middlewares and the command itself returned.
+//
+// Invokes that are stopped before Middleware is reached, e.g. because they
+// are throttled, are not counted as invokes.
+func (c *Collector) Middleware(next bot.CommandFunc) bot.CommandFunc {
+	return func(s *state.State, ctx *plugin.Context) error {
+		if ctx.InvokedCommand == nil {
+			return next(s, ctx)
+		}
+
+		start := time.Now()
+		err := next(s, ctx)
+		c.observe(ctx.InvokedCommand.ID(), time.Since(start))
+
+		return err
+	}
+}
+
+// PostMiddleware is the bot.Middleware counting successful invokes.
+func (c *Collector) PostMiddleware(next bot.CommandFunc) bot.CommandFunc {
+	return func(s *state.State, ctx *plugin.Context) error {
+		if ctx.InvokedCommand != nil {
+			c.metrics(ctx.InvokedCommand.ID(), func(m *commandMetrics) { m.successes++ })
+		}
+
+		return next(s, ctx)
+	}
+}
+
+// WrapErrorHandler wraps the passed error handler, so that the errors of
+// all invokes are counted by their ErrorKind, before being handed to the
+// passed handler.
+//
+// Errors that occur before a command was found, and informational errors,
+// such as errors.Abort, are not counted.
+func (c *Collector) WrapErrorHandler(
+	h func(error, *state.State, *plugin.Context),
+) func(error, *state.State, *plugin.Context) {
+	return func(err error, s *state.State, ctx *plugin.Context) {
+		c.recordError(ctx, err)
+		h(err, s, ctx)
+	}
+}
+
+func (c *Collector) recordError(ctx *plugin.Context, err error) {
+	if ctx.InvokedCommand == nil {
+		return
+	}
+
+	var ierr *errors.InformationalError
+	if errors.As(err, &ierr) {
+		return
+	}
+
+	kind := Kind(err)
+
+	c.metrics(ctx.InvokedCommand.ID(), func(m *commandMetrics) {
+		m.errors[kind]++
+
+		switch kind {
+		case ThrottlingErrorKind:
+			m.throttled++
+		case RestrictionErrorKind:
+			m.restricted++
+		}
+	})
+}
+
+// Kind returns the ErrorKind of the passed error.
+func Kind(err error) ErrorKind {
+	var (
+		terr  *plugin.ThrottlingError
+		rerr  *plugin.RestrictionError
+		aerr  *plugin.ArgumentError
+		uerr  *errors.UserError
+		uinfo *errors.UserInfo
+		perr  *plugin.BotPermissionsError
+		cerr  *plugin.ChannelTypeError
+	)
+
+	switch {
+	case errors.As(err, &terr):
+		return ThrottlingErrorKind
+	case errors.As(err, &rerr):
+		return RestrictionErrorKind
+	case errors.As(err, &aerr):
+		return ArgumentErrorKind
+	case errors.As(err, &uerr), errors.As(err, &uinfo), errors.As(err, &perr), errors.As(err, &cerr):
+		return UserErrorKind
+	default:
+		return InternalErrorKind
+	}
+}
+
+// observe records an invoke of the command with the passed id that took d.
+func (c *Collector) observe(id plugin.ID, d time.Duration) {
+	seconds := d.Seconds()
+
+	c.metrics(id, func(m *commandMetrics) {
+		m.invokes++
+		m.durationSum += seconds
+
+		if i := sort.SearchFloat64s(c.buckets, seconds); i < len(c.buckets) {
+			m.bucketCounts[i]++
+		}
+	})
+}
+
+// metrics calls f with the metrics of the command with the passed id, while
+// holding the Collector's lock.
+func (c *Collector) metrics(id plugin.ID, f func(m *commandMetrics)) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	m, ok := c.commands[id]
+	if !ok {
+		m = &commandMetrics{
+			errors:       make(map[ErrorKind]uint64),
+			bucketCounts: make([]uint64, len(c.buckets)),
+		}
+		c.commands[id] = m
+	}
+
+	f(m)
+}
@@ -0,0 +1,188 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mavolin/disstate/v4/pkg/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/plugin"
+	"github.com/mavolin/adam/pkg/utils/mock"
+)
+
+func TestNewCollector(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewCollector()
+		assert.Equal(t, DefaultBuckets, c.buckets)
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewCollector(1, .5, 2)
+		assert.Equal(t, []float64{.5, 1, 2}, c.buckets)
+	})
+}
+
+func TestKind(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		err    error
+		expect ErrorKind
+	}{
+		{name: "throttling", err: plugin.NewThrottlingError("abc"), expect: ThrottlingErrorKind},
+		{name: "restriction", err: plugin.NewRestrictionError("abc"), expect: RestrictionErrorKind},
+		{name: "argument", err: plugin.NewArgumentError("abc"), expect: ArgumentErrorKind},
+		{name: "user error", err: errors.NewUserError("abc"), expect: UserErrorKind},
+		{name: "user info", err: errors.NewUserInfo("abc"), expect: UserErrorKind},
+		{name: "channel type", err: plugin.NewChannelTypeError(plugin.GuildChannels), expect: UserErrorKind},
+		{name: "internal", err: errors.NewWithStack("abc"), expect: InternalErrorKind},
+		{name: "other", err: errors.New("abc"), expect: InternalErrorKind},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, c.expect, Kind(c.err))
+		})
+	}
+}
+
+func TestCollector_Middleware(t *testing.T) {
+	t.Parallel()
+
+	c := NewCollector(1)
+	ctx := &plugin.Context{InvokedCommand: mock.ResolveCommand(plugin.BuiltInSource, mock.Command{Name: "abc"})}
+
+	expectErr := errors.New("abc")
+
+	f := c.Middleware(func(*state.State, *plugin.Context) error { return expectErr })
+
+	actualErr := f(nil, ctx)
+	assert.Equal(t, expectErr, actualErr)
+
+	m := c.commands[".abc"]
+	require.NotNil(t, m)
+	assert.Equal(t, uint64(1), m.invokes)
+	assert.Equal(t, []uint64{1}, m.bucketCounts)
+}
+
+func TestCollector_PostMiddleware(t *testing.T) {
+	t.Parallel()
+
+	c := NewCollector()
+	ctx := &plugin.Context{InvokedCommand: mock.ResolveCommand(plugin.BuiltInSource, mock.Command{Name: "abc"})}
+
+	f := c.PostMiddleware(func(*state.State, *plugin.Context) error { return nil })
+	require.NoError(t, f(nil, ctx))
+
+	assert.Equal(t, uint64(1), c.commands[".abc"].successes)
+}
+
+func TestCollector_WrapErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no command", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewCollector()
+
+		var called bool
+
+		h := c.WrapErrorHandler(func(error, *state.State, *plugin.Context) { called = true })
+		h(errors.New("abc"), nil, new(plugin.Context))
+
+		assert.True(t, called)
+		assert.Empty(t, c.commands)
+	})
+
+	t.Run("informational", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewCollector()
+		ctx := &plugin.Context{InvokedCommand: mock.ResolveCommand(plugin.BuiltInSource, mock.Command{Name: "abc"})}
+
+		h := c.WrapErrorHandler(func(error, *state.State, *plugin.Context) {})
+		h(errors.Abort, nil, ctx)
+
+		assert.Empty(t, c.commands)
+	})
+
+	t.Run("throttling", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewCollector()
+		ctx := &plugin.Context{InvokedCommand: mock.ResolveCommand(plugin.BuiltInSource, mock.Command{Name: "abc"})}
+
+		h := c.WrapErrorHandler(func(error, *state.State, *plugin.Context) {})
+		h(plugin.NewThrottlingError("abc"), nil, ctx)
+
+		m := c.commands[".abc"]
+		require.NotNil(t, m)
+		assert.Equal(t, uint64(1), m.throttled)
+		assert.Equal(t, uint64(1), m.errors[ThrottlingErrorKind])
+	})
+}
+
+func TestCollector_WritePrometheus(t *testing.T) {
+	t.Parallel()
+
+	c := NewCollector(.5, 1)
+	c.observe(".abc", 250*time.Millisecond)
+	c.observe(".abc", 750*time.Millisecond)
+	c.observe(".abc", 2*time.Second)
+	c.metrics(".abc", func(m *commandMetrics) {
+		m.successes = 2
+		m.errors[InternalErrorKind] = 1
+	})
+
+	expect := strings.Join([]string{
+		"# HELP adam_command_invokes_total Total number of command invokes.",
+		"# TYPE adam_command_invokes_total counter",
+		`adam_command_invokes_total{command=".abc"} 3`,
+		"# HELP adam_command_successes_total Total number of successful command invokes.",
+		"# TYPE adam_command_successes_total counter",
+		`adam_command_successes_total{command=".abc"} 2`,
+		"# HELP adam_command_errors_total Total number of failed command invokes by kind of error.",
+		"# TYPE adam_command_errors_total counter",
+		`adam_command_errors_total{command=".abc",kind="internal"} 1`,
+		"# HELP adam_command_throttled_total Total number of throttled command invokes.",
+		"# TYPE adam_command_throttled_total counter",
+		`adam_command_throttled_total{command=".abc"} 0`,
+		"# HELP adam_command_restricted_total Total number of restricted command invokes.",
+		"# TYPE adam_command_restricted_total counter",
+		`adam_command_restricted_total{command=".abc"} 0`,
+		"# HELP adam_command_duration_seconds Duration of command invokes in seconds.",
+		"# TYPE adam_command_duration_seconds histogram",
+		`adam_command_duration_seconds_bucket{command=".abc",le="0.5"} 1`,
+		`adam_command_duration_seconds_bucket{command=".abc",le="1"} 2`,
+		`adam_command_duration_seconds_bucket{command=".abc",le="+Inf"} 3`,
+		`adam_command_duration_seconds_sum{command=".abc"} 3`,
+		`adam_command_duration_seconds_count{command=".abc"} 3`,
+	}, "\n") + "\n"
+
+	var buf bytes.Buffer
+
+	require.NoError(t, c.WritePrometheus(&buf))
+	assert.Equal(t, expect, buf.String())
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	t.Parallel()
+
+	actual := escapeLabelValue("a\\b\"c\nd")
+	assert.Equal(t, `a\\b\"c\nd`, actual)
+}
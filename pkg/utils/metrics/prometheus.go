@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mavolin/adam/pkg/errors"
+	"github.com/mavolin/adam/pkg/plugin"
+)
+
+// contentType is the content type of the Prometheus text format.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// errorKinds are all ErrorKinds in the order they are exported in.
+var errorKinds = []ErrorKind{
+	ArgumentErrorKind, InternalErrorKind, RestrictionErrorKind, ThrottlingErrorKind, UserErrorKind,
+}
+
+var _ http.Handler = new(Collector)
+
+// ServeHTTP writes the collected metrics in the Prometheus text format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	_ = c.WritePrometheus(w)
+}
+
+// WritePrometheus writes the collected metrics in the Prometheus text format
+// to the passed io.Writer.
+//
+// The following metrics are written, all labeled by the plugin.ID of the
+// command:
+//
+//	adam_command_invokes_total
+//	adam_command_successes_total
+//	adam_command_errors_total (additionally labeled by kind)
+//	adam_command_throttled_total
+//	adam_command_restricted_total
+//	adam_command_duration_seconds (histogram)
+func (c *Collector) WritePrometheus(w io.Writer) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	ids := make([]plugin.ID, 0, len(c.commands))
+	for id := range c.commands {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	bw := bufio.NewWriter(w)
+
+	c.writeCounter(bw, ids, "adam_command_invokes_total", "Total number of command invokes.",
+		func(m *commandMetrics) uint64 { return m.invokes })
+	c.writeCounter(bw, ids, "adam_command_successes_total", "Total number of successful command invokes.",
+		func(m *commandMetrics) uint64 { return m.successes })
+	c.writeErrors(bw, ids)
+	c.writeCounter(bw, ids, "adam_command_throttled_total", "Total number of throttled command invokes.",
+		func(m *commandMetrics) uint64 { return m.throttled })
+	c.writeCounter(bw, ids, "adam_command_restricted_total", "Total number of restricted command invokes.",
+		func(m *commandMetrics) uint64 { return m.restricted })
+	c.writeHistogram(bw, ids)
+
+	return errors.WithStack(bw.Flush())
+}
+
+func (c *Collector) writeCounter(
+	w *bufio.Writer, ids []plugin.ID, name, help string, val func(m *commandMetrics) uint64,
+) {
+	writeHeader(w, name, help, "counter")
+
+	for _, id := range ids {
+		writeSample(w, name, commandLabel(id), strconv.FormatUint(val(c.commands[id]), 10))
+	}
+}
+
+func (c *Collector) writeErrors(w *bufio.Writer, ids []plugin.ID) {
+	const name = "adam_command_errors_total"
+
+	writeHeader(w, name, "Total number of failed command invokes by kind of error.", "counter")
+
+	for _, id := range ids {
+		m := c.commands[id]
+
+		for _, kind := range errorKinds {
+			if n, ok := m.errors[kind]; ok {
+				labels := commandLabel(id) + `,kind="` + string(kind) + `"`
+				writeSample(w, name, labels, strconv.FormatUint(n, 10))
+			}
+		}
+	}
+}
+
+func (c *Collector) writeHistogram(w *bufio.Writer, ids []plugin.ID) {
+	const name = "adam_command_duration_seconds"
+
+	writeHeader(w, name, "Duration of command invokes in seconds.", "histogram")
+
+	for _, id := range ids {
+		m := c.commands[id]
+		label := commandLabel(id)
+
+		var cumulative uint64
+
+		for i, bound := range c.buckets {
+			cumulative += m.bucketCounts[i]
+			writeSample(w, name+"_bucket", label+`,le="`+formatFloat(bound)+`"`,
+				strconv.FormatUint(cumulative, 10))
+		}
+
+		writeSample(w, name+"_bucket", label+`,le="+Inf"`, strconv.FormatUint(m.invokes, 10))
+		writeSample(w, name+"_sum", label, formatFloat(m.durationSum))
+		writeSample(w, name+"_count", label, strconv.FormatUint(m.invokes, 10))
+	}
+}
+
+func writeHeader(w *bufio.Writer, name, help, typ string) {
+	_, _ = w.WriteString("# HELP " + name + " " + help + "\n")
+	_, _ = w.WriteString("# TYPE " + name + " " + typ + "\n")
+}
+
+func writeSample(w *bufio.Writer, name, labels, val string) {
+	_, _ = w.WriteString(name + "{" + labels + "} " + val + "\n")
+}
+
+func commandLabel(id plugin.ID) string {
+	return `command="` + escapeLabelValue(string(id)) + `"`
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabelValue escapes the passed label value as required by the
+// Prometheus text format.
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}